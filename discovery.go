@@ -0,0 +1,94 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package thorlabskdc101
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+	"go.bug.st/serial"
+)
+
+type DeviceInfo struct {
+	PortName     string
+	SerialNumber int32
+	Model        string
+}
+
+/*
+Returns the default serial options used to talk to a KDC101
+on the specified port
+*/
+func SerialOptions(portName string) unicomm.UnicommOptions {
+	return unicomm.UnicommOptions{
+		Protocol: unicomm.Serial,
+		Serial: unicommserial.SerialOptions{
+			PortName:     portName,
+			BaudRate:     115200,
+			DataBits:     8,
+			StopBits:     unicommserial.OneStopBit,
+			Parity:       unicommserial.NoParity,
+			ReadTimeout:  500 * time.Millisecond,
+			WriteTimeout: 500 * time.Millisecond,
+		},
+	}
+}
+
+/*
+Enumerates the serial ports and returns the controllers that
+answer a hardware information request. Ports that cannot be
+opened or do not answer are skipped
+*/
+func ListDevices() ([]DeviceInfo, error) {
+	ports, err := serial.GetPortsList()
+	if err != nil {
+		return nil, err
+	}
+	devices := []DeviceInfo{}
+	for _, port := range ports {
+		controller := New("", "", SerialOptions(port))
+		if err := controller.Connect(); err != nil {
+			continue
+		}
+		info, err := controller.GetInformation()
+		controller.Disconnect()
+		if err != nil {
+			continue
+		}
+		devices = append(devices, DeviceInfo{
+			PortName:     port,
+			SerialNumber: info.SerialNumber,
+			Model:        strings.TrimRight(info.Model, "\x00 "),
+		})
+	}
+	return devices, nil
+}
+
+/*
+Finds the controller with the given serial number among the
+serial ports and returns it connected
+*/
+func OpenBySerial(serialNumber int32, motor MotorType, stage StageType) (*KDC101, error) {
+	devices, err := ListDevices()
+	if err != nil {
+		return nil, err
+	}
+	for _, device := range devices {
+		if device.SerialNumber != serialNumber {
+			continue
+		}
+		controller := New(stage, motor, SerialOptions(device.PortName))
+		if err := controller.Connect(); err != nil {
+			return nil, err
+		}
+		return controller, nil
+	}
+	return nil, fmt.Errorf("no KDC101 found with serial number %d", serialNumber)
+}
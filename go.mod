@@ -2,10 +2,12 @@ module github.com/devicehub-go/thorlabs-kdc101
 
 go 1.23.4
 
-require github.com/devicehub-go/unicomm v0.0.0-20250926191724-f3acc0aeb0c3
+require (
+	github.com/devicehub-go/unicomm v0.0.0-20250926191724-f3acc0aeb0c3
+	go.bug.st/serial v1.6.4
+)
 
 require (
 	github.com/creack/goselect v0.1.2 // indirect
-	go.bug.st/serial v1.6.4 // indirect
 	golang.org/x/sys v0.19.0 // indirect
 )
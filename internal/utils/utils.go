@@ -1,7 +1,7 @@
 /*
 Author: Leonardo Rossi Leao
 Created at: September 26th, 2025
-Last update: September 26th, 2025
+Last update: October 16th, 2026
 */
 
 package utils
@@ -9,69 +9,79 @@ package utils
 import "encoding/binary"
 
 /*
-Converts a little-endian four byte array to a
-32-bit signed integer (long)
+Converts integers to and from byte arrays in a given byte
+order. APT is little-endian, but some relabeled controllers
+send their multi-byte fields big-endian
 */
-func BytesToLong(data []byte) int32 {
-	return int32(binary.LittleEndian.Uint32(data))
+type Codec struct {
+	Order binary.ByteOrder
+}
+
+var LittleEndian = Codec{Order: binary.LittleEndian}
+var BigEndian = Codec{Order: binary.BigEndian}
+
+/*
+Converts a four byte array to a 32-bit signed integer (long)
+*/
+func (c Codec) BytesToLong(data []byte) int32 {
+	return int32(c.Order.Uint32(data))
 }
 
 /*
-Converts a long integer to a little-endian four byte array
+Converts a long integer to a four byte array
 */
-func LongToBytes(value int32) []byte {
+func (c Codec) LongToBytes(value int32) []byte {
 	data := make([]byte, 4)
-	binary.LittleEndian.PutUint32(data, uint32(value))
+	c.Order.PutUint32(data, uint32(value))
 	return data
 }
 
 /*
-Converts a little-endian four byte array to a
-32-bit unsigned integer (dword)
+Converts a four byte array to a 32-bit unsigned integer
+(dword)
 */
-func BytesToDword(data []byte) uint32 {
-	return binary.LittleEndian.Uint32(data)
+func (c Codec) BytesToDword(data []byte) uint32 {
+	return c.Order.Uint32(data)
 }
 
 /*
-Converts a dword integer to a little-endian four byte array
+Converts a dword integer to a four byte array
 */
-func DwordToBytes(value uint32) []byte {
+func (c Codec) DwordToBytes(value uint32) []byte {
 	data := make([]byte, 4)
-	binary.LittleEndian.PutUint32(data, value)
+	c.Order.PutUint32(data, value)
 	return data
 }
 
 /*
-Converts a little-endian two byte array to a 16-bit
-unsigned integer (word)
+Converts a two byte array to a 16-bit unsigned integer
+(word)
 */
-func BytesToWord(data []byte) uint16 {
-	return binary.LittleEndian.Uint16(data)
+func (c Codec) BytesToWord(data []byte) uint16 {
+	return c.Order.Uint16(data)
 }
 
 /*
-Converts a word integer to a little-endian two byte array
+Converts a word integer to a two byte array
 */
-func WordToBytes(value uint16) []byte {
+func (c Codec) WordToBytes(value uint16) []byte {
 	data := make([]byte, 2)
-	binary.LittleEndian.PutUint16(data, value)
+	c.Order.PutUint16(data, value)
 	return data
 }
 
 /*
-Converts a little-endian two byte array to a 16-bit
-signed integer (short)
+Converts a two byte array to a 16-bit signed integer (short)
 */
-func BytesToShort(data []byte) int16 {
-	return int16(binary.LittleEndian.Uint16(data))
+func (c Codec) BytesToShort(data []byte) int16 {
+	return int16(c.Order.Uint16(data))
 }
 
 /*
-Converts a short integer to a little-endian two byte array
+Converts a short integer to a two byte array
 */
-func ShortToBytes(value int16) []byte {
+func (c Codec) ShortToBytes(value int16) []byte {
 	data := make([]byte, 2)
-	binary.LittleEndian.PutUint16(data, uint16(value))
+	c.Order.PutUint16(data, uint16(value))
 	return data
-}
\ No newline at end of file
+}
@@ -0,0 +1,75 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+import "errors"
+
+/*
+Feature groups answered by the connected controller
+*/
+type CapabilitySet struct {
+	Firmware         FirmwareVersion
+	SCurve           bool // Bow index messages (0x04F4)
+	StatusBits       bool // Short status bits message (0x0429)
+	MMIParams        bool // Front panel wheel parameters (0x0520)
+	TriggerIO        bool // Trigger input/output configuration (0x0523)
+	PositionTriggers bool // Position trigger parameters (0x0526)
+}
+
+/*
+Reports which feature groups the connected controller
+supports. Thorlabs does not publish the firmware revision
+adding each message, so rather than relying on a version
+table every group is probed by sending its request message;
+a group is unsupported if the request is not answered. Each
+unsupported group costs one read timeout
+*/
+func (k *KDC101) Capabilities() (CapabilitySet, error) {
+	version, err := k.GetFirmwareVersion()
+	if err != nil {
+		return CapabilitySet{}, err
+	}
+	set := CapabilitySet{Firmware: version}
+	for _, probe := range []struct {
+		request  uint16
+		response uint16
+		result   *bool
+	}{
+		{msgMotReqBowIndex, msgMotGetBowIndex, &set.SCurve},
+		{msgMotReqStatusBits, msgMotGetStatusBits, &set.StatusBits},
+		{msgMotReqKCubeMMIParams, msgMotGetKCubeMMIParams, &set.MMIParams},
+		{msgMotReqKCubeTrigIOConfig, msgMotGetKCubeTrigIOConfig, &set.TriggerIO},
+		{msgMotReqKCubePosTrigParams, msgMotGetKCubePosTrigParams, &set.PositionTriggers},
+	} {
+		supported, err := k.probe(probe.request, probe.response)
+		if err != nil {
+			return CapabilitySet{}, err
+		}
+		*probe.result = supported
+	}
+	return set, nil
+}
+
+/*
+Sends a channel 1 request and returns true if it is answered
+with the expected message
+*/
+func (k *KDC101) probe(request, response uint16) (bool, error) {
+	msg, err := k.RequestData(HeaderMessage{
+		ID:          request,
+		Parameter1:  0x01,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+	if errors.Is(err, ErrInvalidResponseLength) || errors.Is(err, ErrHeaderOnlyFrame) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return msg.ID == response, nil
+}
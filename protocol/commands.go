@@ -1,27 +1,33 @@
 /*
 Author: Leonardo Rossi Leao
 Created at: September 26th, 2025
-Last update: September 26th, 2025
+Last update: October 16th, 2026
 */
 
 package protocol
 
 import (
+	"context"
 	"fmt"
-
-	"github.com/devicehub-go/thorlabs-kdc101/internal/utils"
+	"time"
 )
 
 type Direction uint8
 type StopMode  uint8
 
+type FirmwareVersion struct {
+	Major   uint8
+	Interim uint8
+	Minor   uint8
+}
+
 type HwInformation struct {
 	SerialNumber    int32
 	Model           string
 	Type            uint16
-	FirmwareVersion []byte
+	FirmwareVersion []byte // Minor, interim and major revision, unused
 	HardwareVersion uint16
-	ModState        uint16
+	ModState        uint16 // Hardware modification stage, a plain counter
 	NumberChannels  uint16
 }
 
@@ -33,17 +39,66 @@ const (
 	Soft   StopMode = 0x02
 )
 
+/*
+Interval between the identify messages sent by LocateUnit,
+shorter than a single flash so the LEDs keep blinking
+*/
+const LocateInterval = time.Second
+
+var ErrInvalidDirection = fmt.Errorf("invalid direction")
+var ErrInvalidStopMode = fmt.Errorf("invalid stop mode")
+
+/*
+Returns an error if the direction is not Forward or Reverse
+*/
+func validateDirection(direction Direction) error {
+	if direction != Forward && direction != Reverse {
+		return fmt.Errorf("%w: %d", ErrInvalidDirection, direction)
+	}
+	return nil
+}
+
+/*
+Returns an error if the stop mode is not Abrupt or Soft
+*/
+func validateStopMode(mode StopMode) error {
+	if mode != Abrupt && mode != Soft {
+		return fmt.Errorf("%w: %d", ErrInvalidStopMode, mode)
+	}
+	return nil
+}
+
 /*
 Instruct hardware unit to identify itself by flashing
-its front panel LEDs.
+its front panel LEDs. The channel tells "which axis" on
+multi-channel controllers; single-channel ones such as the
+KDC101 ignore it. The flash duration is fixed by the
+firmware, the protocol has no parameter for it
 */
 func (k *KDC101) Identify(channel uint8) error {
-	if channel != 1 {
-		return ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
 	}
 	return k.WriteHeaderOnly(HeaderMessage{
-		ID:          0x0223,
-		Parameter1:  byte(1 << (channel - 1)),
+		ID:          msgModIdentify,
+		Parameter1:  ident,
+		Parameter2:  0x00,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+}
+
+/*
+Instructs the controller as a whole to identify itself,
+telling "which box" in a rack of controllers: the module
+level identify is sent with a zero channel ident, as the
+APT protocol does for single-channel controllers
+*/
+func (k *KDC101) IdentifyUnit() error {
+	return k.WriteHeaderOnly(HeaderMessage{
+		ID:          msgModIdentify,
+		Parameter1:  0x00,
 		Parameter2:  0x00,
 		Destination: GenericUnit,
 		Source:      Host,
@@ -51,11 +106,35 @@ func (k *KDC101) Identify(channel uint8) error {
 }
 
 /*
-Request hardware information from the controller
+Keeps the controller flashing its front panel LEDs until the
+context is cancelled, which ends the call without error, so
+an operator can find the unit in a rack. The module level
+identify is re-sent every LocateInterval, since a single
+one only flashes briefly
+*/
+func (k *KDC101) LocateUnit(ctx context.Context) error {
+	for {
+		if err := k.IdentifyUnit(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(LocateInterval):
+		}
+	}
+}
+
+/*
+Request hardware information from the controller. Only the
+fields up to the firmware version are required; the
+hardware version, modification state and channel count at
+the end of the block are left zero if the firmware sends a
+shorter one
 */
 func (k *KDC101) GetInformation() (HwInformation, error) {
 	response, err := k.RequestData(HeaderMessage{
-		ID:          0x0005,
+		ID:          msgHwReqInfo,
 		Parameter1:  0x00,
 		Parameter2:  0x00,
 		Destination: GenericUnit,
@@ -64,32 +143,65 @@ func (k *KDC101) GetInformation() (HwInformation, error) {
 	if err != nil {
 		return HwInformation{}, err
 	}
+	if err := expectResponse(response, msgHwGetInfo); err != nil {
+		return HwInformation{}, err
+	}
 	data := response.Data
-	if len(data) < 84 {
-		return HwInformation{}, fmt.Errorf("invalid response length")
+	if len(data) < 18 {
+		return HwInformation{}, ErrInvalidResponseLength
 	}
-	return HwInformation{
-		SerialNumber:    utils.BytesToLong(data[0:4]),
+	info := HwInformation{
+		SerialNumber:    k.codec().BytesToLong(data[0:4]),
 		Model:           string(data[4:12]),
-		Type:            utils.BytesToWord(data[12:14]),
+		Type:            k.codec().BytesToWord(data[12:14]),
 		FirmwareVersion: data[14:18],
-		HardwareVersion: utils.BytesToWord(data[78:80]),
-		ModState:        utils.BytesToWord(data[80:82]),
-		NumberChannels:  utils.BytesToWord(data[82:84]),
+	}
+	if len(data) >= 80 {
+		info.HardwareVersion = k.codec().BytesToWord(data[78:80])
+	}
+	if len(data) >= 82 {
+		info.ModState = k.codec().BytesToWord(data[80:82])
+	}
+	if len(data) >= 84 {
+		info.NumberChannels = k.codec().BytesToWord(data[82:84])
+	}
+	return info, nil
+}
+
+/*
+Request the firmware version from the controller
+*/
+func (k *KDC101) GetFirmwareVersion() (FirmwareVersion, error) {
+	info, err := k.GetInformation()
+	if err != nil {
+		return FirmwareVersion{}, err
+	}
+	return FirmwareVersion{
+		Major:   info.FirmwareVersion[2],
+		Interim: info.FirmwareVersion[1],
+		Minor:   info.FirmwareVersion[0],
 	}, nil
 }
 
+/*
+Formats the version as major.interim.minor
+*/
+func (v FirmwareVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Interim, v.Minor)
+}
+
 /*
 Start a home move sequence on the specified channel
 in accordance with the home paramters set
 */
 func (k *KDC101) StartHomeMove(channel uint8) error {
-	if channel != 1 {
-		return ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
 	}
 	return k.WriteHeaderOnly(HeaderMessage{
-		ID:          0x0443,
-		Parameter1:  byte(1 << (channel - 1)),
+		ID:          msgMotMoveHome,
+		Parameter1:  ident,
 		Destination: GenericUnit,
 		Source:      Host,
 	})
@@ -100,12 +212,13 @@ Starts a relative move on the specified channel
 in accordance with the distance parameters set
 */
 func (k *KDC101) StartRelativeMove(channel uint8) error {
-	if channel != 1 {
-		return ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
 	}
 	return k.WriteHeaderOnly(HeaderMessage{
-		ID:          0x0448,
-		Parameter1:  byte(1 << (channel - 1)),
+		ID:          msgMotMoveRelative,
+		Parameter1:  ident,
 		Destination: GenericUnit,
 		Source:      Host,
 	})
@@ -113,20 +226,29 @@ func (k *KDC101) StartRelativeMove(channel uint8) error {
 
 /*
 Starts a relative move on the specified channel
-with the target distance
+with the target distance. It shares its message ID with
+StartRelativeMove but is sent as a data packet
 */
 func (k *KDC101) MoveRelativeDistance(channel uint8, distance float64) error {
-	if channel != 1 {
-		return ErrChannelNotSupported
+	return k.MoveRelativeCounts(channel, k.PositionToCounts(distance))
+}
+
+/*
+Starts a relative move on the specified channel by the
+given number of encoder counts, without unit conversion
+*/
+func (k *KDC101) MoveRelativeCounts(channel uint8, counts int32) error {
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
 	}
-	var counts int32 = k.PositionToCounts(distance)
 	data := []byte{
-		byte(1 << (channel - 1)),
+		ident,
 		0x00,
 	}
-	data = append(data, utils.LongToBytes(counts)...)
+	data = append(data, k.codec().LongToBytes(counts)...)
 	return k.WriteData(DataMessage{
-		ID:          0x0448,
+		ID:          msgMotMoveRelativeDistance,
 		Data:        data,
 		DataLength:  uint16(len(data)),
 		Destination: GenericUnit,
@@ -134,17 +256,43 @@ func (k *KDC101) MoveRelativeDistance(channel uint8, distance float64) error {
 	})
 }
 
+/*
+Moves one step forward on the specified channel using the
+step size stored on the device
+*/
+func (k *KDC101) StepForward(channel uint8) error {
+	return k.StartRelativeMove(channel)
+}
+
+/*
+Moves one step backwards on the specified channel. The step
+size is read from the device only if it is not known yet
+*/
+func (k *KDC101) StepReverse(channel uint8) error {
+	if _, err := k.channelBitmask(channel); err != nil {
+		return err
+	}
+	if k.relativeStep == nil {
+		if _, err := k.GetRelativeStepSize(channel); err != nil {
+			return err
+		}
+	}
+	return k.MoveRelativeDistance(channel, -*k.relativeStep)
+}
+
 /*
 Starts an absolute mvoe on the specified channel
 in accordance with the absolute move parameters set
 */
 func (k *KDC101) StartAbsoluteMove(channel uint8) error {
-	if channel != 1 {
-		return ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
 	}
+	k.absoluteTarget = nil // Moving to the stored position
 	return k.WriteHeaderOnly(HeaderMessage{
-		ID:          0x0453,
-		Parameter1:  byte(1 << (channel - 1)),
+		ID:          msgMotMoveAbsolute,
+		Parameter1:  ident,
 		Destination: GenericUnit,
 		Source:      Host,
 	})
@@ -155,34 +303,104 @@ Starts an absolute move on the specified channel
 with the target position
 */
 func (k *KDC101) MoveAbsolutePosition(channel uint8, position float64) error {
-	if channel != 1 {
-		return ErrChannelNotSupported
+	if _, err := k.channelBitmask(channel); err != nil {
+		return err
 	}
-	var counts int32 = k.PositionToCounts(position)
+	if err := k.validateTravel(position); err != nil {
+		return err
+	}
+	return k.MoveAbsoluteCounts(channel, k.PositionToCounts(position))
+}
+
+/*
+Starts an absolute move on the specified channel to the
+given encoder count, without unit conversion. The travel
+range is not validated, since the stage may be unknown, but
+the target is clamped to it when ClampMoves is set
+*/
+func (k *KDC101) MoveAbsoluteCounts(channel uint8, counts int32) error {
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
+	}
+	counts = k.clampCounts(counts)
 	data := []byte{
-		byte(1 << (channel - 1)),
+		ident,
 		0x00,
 	}
-	data = append(data, utils.LongToBytes(counts)...)
-	return k.WriteData(DataMessage{
-		ID:          0x0453,
+	data = append(data, k.codec().LongToBytes(counts)...)
+	err = k.WriteData(DataMessage{
+		ID:          msgMotMoveAbsolutePosition,
 		Data:        data,
 		DataLength:  uint16(len(data)),
 		Destination: GenericUnit,
 		Source:      Host,
 	})
+	if err != nil {
+		return err
+	}
+	k.absoluteTarget = &counts
+	return nil
+}
+
+/*
+Applies the velocity profile and starts an absolute move on
+the specified channel as a single atomic sequence. The
+controller applies velocity parameters to the move in
+progress, so when restore is set the call waits for the
+move to end, with a timeout derived from the estimated
+travel time, before writing the previous profile back. If
+the wait fails the new profile is left in place, since
+restoring it would change the move still running
+*/
+func (k *KDC101) MoveAbsoluteWithProfile(channel uint8, position float64, profile VelocityProfile, restore bool) error {
+	if _, err := k.channelBitmask(channel); err != nil {
+		return err
+	}
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	var previous VelocityProfile
+	if restore {
+		current, err := k.GetTrapezoidalVelocity(channel)
+		if err != nil {
+			return err
+		}
+		previous = current
+	}
+	if err := k.setTrapezoidalVelocity(channel, profile); err != nil {
+		return err
+	}
+	if !restore {
+		return k.MoveAbsolutePosition(channel, position)
+	}
+	timeout, err := k.absoluteMoveTimeout(channel, position, 0)
+	if err != nil {
+		return err
+	}
+	if err := k.MoveAbsolutePosition(channel, position); err != nil {
+		return err
+	}
+	if err := k.waitForStop(context.Background(), channel, timeout); err != nil {
+		return fmt.Errorf("waiting to restore the velocity profile: %w", err)
+	}
+	return k.setTrapezoidalVelocity(channel, previous)
 }
 
 /*
 Start a jog move on the specified motor channel
 */
 func (k *KDC101) StartJogMove(channel uint8, direction Direction) error {
-	if channel != 1 {
-		return ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
+	}
+	if err := validateDirection(direction); err != nil {
+		return err
 	}
 	return k.WriteHeaderOnly(HeaderMessage{
-		ID:          0x046A,
-		Parameter1:  byte(1 << (channel - 1)),
+		ID:          msgMotMoveJog,
+		Parameter1:  ident,
 		Parameter2:  byte(direction),
 		Destination: GenericUnit,
 		Source:      Host,
@@ -195,12 +413,16 @@ using the velocity parameters set until a stop command
 or limit is reached.
 */
 func (k *KDC101) MoveContinuous(channel uint8, direction Direction) error {
-	if channel != 1 {
-		return ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
+	}
+	if err := validateDirection(direction); err != nil {
+		return err
 	}
 	return k.WriteHeaderOnly(HeaderMessage{
-		ID:          0x0457,
-		Parameter1:  byte(1 << (channel - 1)),
+		ID:          msgMotMoveVelocity,
+		Parameter1:  ident,
 		Parameter2:  byte(direction),
 		Destination: GenericUnit,
 		Source:      Host,
@@ -211,14 +433,36 @@ func (k *KDC101) MoveContinuous(channel uint8, direction Direction) error {
 Stops the motor on the specified channel
 */
 func (k *KDC101) Stop(channel uint8, mode StopMode) error {
-	if channel != 1 {
-		return ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
+	}
+	if err := validateStopMode(mode); err != nil {
+		return err
 	}
 	return k.WriteHeaderOnly(HeaderMessage{
-		ID:          0x0465,
-		Parameter1:  byte(1 << (channel - 1)),
+		ID:          msgMotMoveStop,
+		Parameter1:  ident,
 		Parameter2:  byte(mode),
 		Destination: GenericUnit,
 		Source:      Host,
 	})
 }
+
+/*
+Starts moving continuously in the specified direction, meant
+to be paired with JogStop for press and release jog buttons.
+Unlike StartJogMove it does not depend on the jog mode
+*/
+func (k *KDC101) JogStart(channel uint8, direction Direction) error {
+	return k.MoveContinuous(channel, direction)
+}
+
+/*
+Ends a move started by JogStart with a profiled stop. The
+controller ignores the stop if the motor is not moving, so
+it is safe to call at any time
+*/
+func (k *KDC101) JogStop(channel uint8) error {
+	return k.Stop(channel, Soft)
+}
@@ -0,0 +1,178 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/thorlabs-kdc101/protocol"
+)
+
+func TestInvalidDirection(t *testing.T) {
+	controller := &protocol.KDC101{StageType: "MTS25-Z8", MotorType: "Brushed"}
+
+	for _, direction := range []protocol.Direction{0x00, 0x03, 0xFF} {
+		if err := controller.StartJogMove(1, direction); !errors.Is(err, protocol.ErrInvalidDirection) {
+			t.Errorf("StartJogMove direction %d: got %v, expected ErrInvalidDirection", direction, err)
+		}
+		if err := controller.MoveContinuous(1, direction); !errors.Is(err, protocol.ErrInvalidDirection) {
+			t.Errorf("MoveContinuous direction %d: got %v, expected ErrInvalidDirection", direction, err)
+		}
+	}
+}
+
+func TestInvalidStopMode(t *testing.T) {
+	controller := &protocol.KDC101{StageType: "MTS25-Z8", MotorType: "Brushed"}
+
+	for _, mode := range []protocol.StopMode{0x00, 0x03, 0xFF} {
+		if err := controller.Stop(1, mode); !errors.Is(err, protocol.ErrInvalidStopMode) {
+			t.Errorf("Stop mode %d: got %v, expected ErrInvalidStopMode", mode, err)
+		}
+	}
+}
+
+func TestRelativeMoveFrames(t *testing.T) {
+	controller, transport := newFakeController(nil)
+
+	if err := controller.StartRelativeMove(1); err != nil {
+		t.Fatalf("StartRelativeMove: %v", err)
+	}
+	if err := controller.MoveRelativeDistance(1, 1.0); err != nil {
+		t.Fatalf("MoveRelativeDistance: %v", err)
+	}
+	if len(transport.written) != 2 {
+		t.Fatalf("got %d frames written, expected 2", len(transport.written))
+	}
+
+	start := transport.written[0]
+	if len(start) != 6 || start[0] != 0x48 || start[1] != 0x04 || start[4]&0x80 != 0 {
+		t.Errorf("StartRelativeMove: got % X, expected a 6 byte header only 0x0448 frame", start)
+	}
+	distance := transport.written[1]
+	if len(distance) != 12 || distance[0] != 0x48 || distance[1] != 0x04 || distance[4]&0x80 == 0 {
+		t.Errorf("MoveRelativeDistance: got % X, expected a 12 byte 0x0448 data packet", distance)
+	}
+	if length := int(distance[2]) | int(distance[3])<<8; length != 6 {
+		t.Errorf("MoveRelativeDistance: got data length %d, expected 6", length)
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		switch frame[0] {
+		case 0x05: // Hardware information cut short
+			return []byte{0x06, 0x00, 0x04, 0x00, 0x81, 0x50, 0x00, 0x00, 0x00, 0x00}
+		case 0x11: // Position requested, velocity parameters sent
+			return []byte{
+				0x15, 0x04, 0x06, 0x00, 0x81, 0x50,
+				0x01, 0x00, 0x00, 0x00, 0x00, 0x00,
+			}
+		}
+		return nil
+	})
+
+	if _, err := controller.GetInformation(); !errors.Is(err, protocol.ErrInvalidResponseLength) {
+		t.Errorf("GetInformation: got %v, expected ErrInvalidResponseLength", err)
+	}
+	if _, err := controller.GetPosition(1); !errors.Is(err, protocol.ErrUnexpectedMessageID) {
+		t.Errorf("GetPosition: got %v, expected ErrUnexpectedMessageID", err)
+	}
+	_, err := controller.GetTrapezoidalVelocity(1)
+	if !errors.Is(err, protocol.ErrTimeout) || !errors.Is(err, protocol.ErrInvalidResponseLength) {
+		t.Errorf("GetTrapezoidalVelocity: got %v, expected ErrTimeout and ErrInvalidResponseLength", err)
+	}
+	if !errors.Is(protocol.ErrMoveTimeout, protocol.ErrTimeout) {
+		t.Error("ErrMoveTimeout does not match ErrTimeout")
+	}
+}
+
+func TestShortInformationBlock(t *testing.T) {
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		response := []byte{0x06, 0x00, 78, 0x00, 0x81, 0x50}
+		data := make([]byte, 78)
+		copy(data, []byte{0x39, 0x30, 0x00, 0x00, 'K', 'D', 'C', '1', '0', '1'})
+		data[14], data[15], data[16] = 4, 2, 1
+		return append(response, data...)
+	})
+
+	info, err := controller.GetInformation()
+	if err != nil {
+		t.Fatalf("GetInformation: %v", err)
+	}
+	if info.SerialNumber != 12345 || info.NumberChannels != 0 {
+		t.Errorf("got serial number %d and %d channels, expected 12345 and 0", info.SerialNumber, info.NumberChannels)
+	}
+	version, err := controller.GetFirmwareVersion()
+	if err != nil || version.String() != "1.2.4" {
+		t.Errorf("GetFirmwareVersion: got %v, %v, expected 1.2.4", version, err)
+	}
+}
+
+func TestClampMoves(t *testing.T) {
+	controller, transport := newFakeController(nil)
+	controller.ValidateTravel = true
+	if err := controller.MoveAbsolutePosition(1, 30); !errors.Is(err, protocol.ErrOutOfTravelRange) {
+		t.Fatalf("got %v, expected ErrOutOfTravelRange without clamping", err)
+	}
+	controller.ClampMoves = true
+	for position, expected := range map[float64]int32{30: controller.MaxTravelCounts(), -1: 0} {
+		if err := controller.MoveAbsolutePosition(1, position); err != nil {
+			t.Fatalf("MoveAbsolutePosition(%v): %v", position, err)
+		}
+		frame := transport.written[len(transport.written)-1]
+		if counts := int32(binary.LittleEndian.Uint32(frame[8:12])); counts != expected {
+			t.Errorf("%v mm: got %d counts, expected %d", position, counts, expected)
+		}
+	}
+
+	controller.StageType = "PRM1-Z8"
+	for _, clamp := range []bool{false, true} {
+		controller.ClampMoves = clamp
+		for _, angle := range []float64{-10, 370} {
+			if err := controller.MoveAbsolutePosition(1, angle); err != nil {
+				t.Fatalf("rotary move to %v° (clamp %v): %v", angle, clamp, err)
+			}
+			frame := transport.written[len(transport.written)-1]
+			if counts := int32(binary.LittleEndian.Uint32(frame[8:12])); counts != controller.PositionToCounts(angle) {
+				t.Errorf("rotary move to %v°: got %d counts, expected %d", angle, counts, controller.PositionToCounts(angle))
+			}
+		}
+	}
+}
+
+func TestIdentifyUnit(t *testing.T) {
+	controller, transport := newFakeController(nil)
+	if err := controller.IdentifyUnit(); err != nil {
+		t.Fatalf("IdentifyUnit: %v", err)
+	}
+	if err := controller.Identify(1); err != nil {
+		t.Fatalf("Identify: %v", err)
+	}
+	unit, channel := transport.written[0], transport.written[1]
+	if unit[0] != 0x23 || unit[1] != 0x02 || unit[2] != 0x00 {
+		t.Errorf("got % X, expected a module identify with channel ident 0", unit)
+	}
+	if channel[2] != 0x01 {
+		t.Errorf("got % X, expected an identify for channel 1", channel)
+	}
+}
+
+func TestLocateUnitStopsOnCancel(t *testing.T) {
+	controller, transport := newFakeController(nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := controller.LocateUnit(ctx); err != nil {
+		t.Fatalf("LocateUnit: %v", err)
+	}
+	if len(transport.written) != 1 || transport.written[0][0] != 0x23 {
+		t.Errorf("got %d frames, expected a single identify within the first interval", len(transport.written))
+	}
+}
@@ -0,0 +1,168 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+/*
+Aggregates the stage settings applied by Configure. Nil
+fields are left unchanged on the device
+*/
+type StageConfig struct {
+	Velocity    *VelocityProfile       `json:"velocity,omitempty"`
+	Jog         *JogParameters         `json:"jog,omitempty"`
+	Home        *HomeParameters        `json:"home,omitempty"`
+	Backlash    *float64               `json:"backlash,omitempty"` // mm
+	LimitSwitch *LimitSwitchParameters `json:"limit_switch,omitempty"`
+}
+
+/*
+Applies every setting present in the configuration to the
+specified channel, stopping at the first one that fails
+*/
+func (k *KDC101) Configure(channel uint8, cfg StageConfig) error {
+	if _, err := k.channelBitmask(channel); err != nil {
+		return err
+	}
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if cfg.Velocity != nil {
+		if err := k.setTrapezoidalVelocity(channel, *cfg.Velocity); err != nil {
+			return fmt.Errorf("velocity profile: %w", err)
+		}
+	}
+	if cfg.Jog != nil {
+		if err := k.SetJogParameters(channel, *cfg.Jog); err != nil {
+			return fmt.Errorf("jog parameters: %w", err)
+		}
+	}
+	if cfg.Home != nil {
+		if err := k.SetHomeParameters(channel, *cfg.Home); err != nil {
+			return fmt.Errorf("home parameters: %w", err)
+		}
+	}
+	if cfg.Backlash != nil {
+		if err := k.SetBacklashDistance(channel, *cfg.Backlash); err != nil {
+			return fmt.Errorf("backlash distance: %w", err)
+		}
+	}
+	if cfg.LimitSwitch != nil {
+		if err := k.SetLimitSwitchParameters(channel, *cfg.LimitSwitch); err != nil {
+			return fmt.Errorf("limit switch parameters: %w", err)
+		}
+	}
+	return nil
+}
+
+/*
+Named motion regime kept in memory, e.g. "fast coarse" or
+"slow fine". A nil backlash is left unchanged on the device
+*/
+type Preset struct {
+	Velocity VelocityProfile `json:"velocity"`
+	Backlash *float64        `json:"backlash,omitempty"` // mm
+}
+
+var ErrUnknownPreset = fmt.Errorf("unknown preset")
+
+/*
+Stores the preset under the given name, replacing any
+previous one with the same name
+*/
+func (k *KDC101) SavePreset(name string, p Preset) {
+	k.presetMutex.Lock()
+	defer k.presetMutex.Unlock()
+
+	if k.presets == nil {
+		k.presets = make(map[string]Preset)
+	}
+	k.presets[name] = p
+}
+
+/*
+Applies every setting of the named preset to the specified
+channel in one call, like Configure
+*/
+func (k *KDC101) ApplyPreset(channel uint8, name string) error {
+	k.presetMutex.Lock()
+	preset, ok := k.presets[name]
+	k.presetMutex.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownPreset, name)
+	}
+	return k.Configure(channel, StageConfig{
+		Velocity: &preset.Velocity,
+		Backlash: preset.Backlash,
+	})
+}
+
+/*
+Reads every stage setting from the specified channel. Failed
+reads do not abort the snapshot: the settings that could be
+read are returned along with the joined errors of the others
+*/
+func (k *KDC101) GetAllParameters(channel uint8) (StageConfig, error) {
+	if _, err := k.channelBitmask(channel); err != nil {
+		return StageConfig{}, err
+	}
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	var cfg StageConfig
+	var errs []error
+
+	if velocity, err := k.GetTrapezoidalVelocity(channel); err != nil {
+		errs = append(errs, fmt.Errorf("velocity profile: %w", err))
+	} else {
+		cfg.Velocity = &velocity
+	}
+	if jog, err := k.GetJogParameters(channel); err != nil {
+		errs = append(errs, fmt.Errorf("jog parameters: %w", err))
+	} else {
+		cfg.Jog = &jog
+	}
+	if home, err := k.GetHomeParameters(channel); err != nil {
+		errs = append(errs, fmt.Errorf("home parameters: %w", err))
+	} else {
+		cfg.Home = &home
+	}
+	if backlash, err := k.GetBacklashDistance(channel); err != nil {
+		errs = append(errs, fmt.Errorf("backlash distance: %w", err))
+	} else {
+		cfg.Backlash = &backlash
+	}
+	if limits, err := k.GetLimitSwitchParameters(channel); err != nil {
+		errs = append(errs, fmt.Errorf("limit switch parameters: %w", err))
+	} else {
+		cfg.LimitSwitch = &limits
+	}
+	return cfg, errors.Join(errs...)
+}
+
+/*
+Loads a stage configuration from a JSON file. Distances are
+expressed in millimeters, velocities in mm/s and
+accelerations in mm/s²
+*/
+func LoadStageConfig(path string) (StageConfig, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return StageConfig{}, err
+	}
+	var cfg StageConfig
+	if err := json.Unmarshal(content, &cfg); err != nil {
+		return StageConfig{}, fmt.Errorf("invalid stage config %s: %w", path, err)
+	}
+	return cfg, nil
+}
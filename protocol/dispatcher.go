@@ -0,0 +1,112 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+/*
+Routes the frames sent by the controller to handlers
+registered by message ID. Header only frames are delivered
+as data messages without length whose data holds the two
+header parameters
+*/
+type Dispatcher struct {
+	device   *KDC101
+	handlers map[uint16]func(DataMessage)
+	fallback func(DataMessage)
+
+	mutex sync.RWMutex
+}
+
+/*
+Creates a new dispatcher reading frames from the device
+*/
+func NewDispatcher(device *KDC101) *Dispatcher {
+	return &Dispatcher{
+		device:   device,
+		handlers: make(map[uint16]func(DataMessage)),
+	}
+}
+
+/*
+Registers the handler called for every frame with the
+given message ID, replacing any previous one
+*/
+func (d *Dispatcher) On(id uint16, handler func(DataMessage)) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.handlers[id] = handler
+}
+
+/*
+Registers the handler called for frames whose message ID
+has no handler registered
+*/
+func (d *Dispatcher) OnDefault(handler func(DataMessage)) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.fallback = handler
+}
+
+/*
+Reads frames and dispatches them until the context is
+cancelled or a read fails
+*/
+func (d *Dispatcher) Run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		msg, err := d.device.ReadData()
+		var frame *HeaderOnlyFrameError
+		var response *HardwareResponseError
+		if errors.As(err, &response) {
+			msg = DataMessage{
+				ID:          response.ID,
+				Destination: Endpoint(response.frame[4]),
+				Source:      Endpoint(response.frame[5]),
+				Data:        append([]byte{}, response.frame[6:]...),
+			}
+			if response.ID == msgHwResponse {
+				msg.Data = []byte{response.frame[2], response.frame[3]}
+			}
+		} else if errors.As(err, &frame) {
+			msg = DataMessage{
+				ID:          frame.Header.ID,
+				Destination: frame.Header.Destination,
+				Source:      frame.Header.Source,
+				Data:        []byte{frame.Header.Parameter1, frame.Header.Parameter2},
+			}
+		} else if errors.Is(err, ErrInvalidResponseLength) {
+			continue // Nothing received within the read timeout
+		} else if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return err
+		}
+		d.dispatch(msg)
+	}
+	return ctx.Err()
+}
+
+/*
+Calls the handler registered for the message
+*/
+func (d *Dispatcher) dispatch(msg DataMessage) {
+	d.mutex.RLock()
+	handler, ok := d.handlers[msg.ID]
+	if !ok {
+		handler = d.fallback
+	}
+	d.mutex.RUnlock()
+
+	if handler != nil {
+		handler(msg)
+	}
+}
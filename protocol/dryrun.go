@@ -0,0 +1,283 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+)
+
+/*
+Response synthesized for a request in dry run mode
+*/
+type dryRunResponse struct {
+	ID         uint16
+	DataLength uint16 // Zero for header only responses
+}
+
+/*
+Responses to the requests issued by this library
+*/
+var dryRunResponses = map[uint16]dryRunResponse{
+	msgHwReqInfo:                {ID: msgHwGetInfo, DataLength: 84},
+	msgModReqChanEnableState:    {ID: msgModGetChanEnableState},
+	msgMotReqPosCounter:         {ID: msgMotGetPosCounter, DataLength: 6},
+	msgMotReqVelParams:          {ID: msgMotGetVelParams, DataLength: 14},
+	msgMotReqJogParams:          {ID: msgMotGetJogParams, DataLength: 22},
+	msgMotReqLimSwitchParams:    {ID: msgMotGetLimSwitchParams, DataLength: 16},
+	msgMotReqGenMoveParams:      {ID: msgMotGetGenMoveParams, DataLength: 6},
+	msgMotReqHomeParams:         {ID: msgMotGetHomeParams, DataLength: 14},
+	msgMotReqMoveRelParams:      {ID: msgMotGetMoveRelParams, DataLength: 6},
+	msgMotReqMoveAbsParams:      {ID: msgMotGetMoveAbsParams, DataLength: 6},
+	msgMotReqStatusUpdate:       {ID: msgMotGetStatusUpdate, DataLength: 14},
+	msgMotReqDCStatusUpdate:     {ID: msgMotGetDCStatusUpdate, DataLength: 14},
+	msgMotReqBowIndex:           {ID: msgMotGetBowIndex, DataLength: 4},
+	msgMotReqStatusBits:         {ID: msgMotGetStatusBits, DataLength: 6},
+	msgMotReqKCubeMMIParams:     {ID: msgMotGetKCubeMMIParams, DataLength: 28},
+	msgMotReqKCubeTrigIOConfig:  {ID: msgMotGetKCubeTrigIOConfig, DataLength: 12},
+	msgMotReqKCubePosTrigParams: {ID: msgMotGetKCubePosTrigParams, DataLength: 34},
+}
+
+/*
+Status bits reported in dry run mode: connected, homed,
+settled and power ok, plus enabled unless disabled
+*/
+const dryRunStatusBits uint32 = 0x10002500
+
+/*
+Velocity parameters answered until others are set: 2.3 mm/s
+and 1.5 mm/s² in the counts of the Z8 stages, so the moves
+deriving their timeout from the profile can run
+*/
+var dryRunVelParams = []byte{
+	0x01, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x8C, 0x01, 0x00, 0x00,
+	0xC3, 0x20, 0x1B, 0x00,
+}
+
+/*
+Transport that never touches a port: it records every frame
+written and answers requests with well-formed responses of
+a plausible idle stage. Moves set the position reported at
+once, homing is shown by one status before the stage reads
+back homed, parameters set are read back and the velocity
+profile is non-zero, so command sequences, blocking moves
+included, can be built and tested without hardware. End of
+move messages start suspended, so they never answer another
+request, and are sent once resumed
+*/
+type DryRunTransport struct {
+	frames  [][]byte
+	pending []byte
+
+	position  int32             // Encoder counts
+	homing    bool              // Shown by the next status
+	disabled  bool              // Channel disabled with Enable
+	endOfMove bool              // End of move messages resumed
+	params    map[uint16][]byte // Data set, keyed by the request reading it back
+
+	mutex sync.Mutex
+}
+
+func (d *DryRunTransport) Connect() error    { return nil }
+func (d *DryRunTransport) Disconnect() error { return nil }
+func (d *DryRunTransport) IsConnected() bool { return true }
+
+/*
+Reads the synthesized responses queued by previous writes
+*/
+func (d *DryRunTransport) Read(size uint) ([]byte, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	n := min(int(size), len(d.pending))
+	data := d.pending[:n]
+	d.pending = d.pending[n:]
+	return data, nil
+}
+
+func (d *DryRunTransport) ReadUntil(delimiter string) ([]byte, error) {
+	return nil, fmt.Errorf("read until is not supported in dry run mode")
+}
+
+/*
+Records the frame, applies it to the simulated stage and
+queues the response to it, if any
+*/
+func (d *DryRunTransport) Write(message []byte) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	frame := append([]byte{}, message...)
+	d.frames = append(d.frames, frame)
+	if d.params == nil {
+		d.params = map[uint16][]byte{msgMotReqVelParams: dryRunVelParams}
+	}
+	if len(frame) < 6 {
+		return nil
+	}
+	request, _ := DecodeHeaderMessage(frame)
+	if frame[4]&0x80 != 0 {
+		d.applyData(request.ID, frame[6:])
+		return nil
+	}
+	d.applyHeader(request)
+
+	response, ok := dryRunResponses[request.ID]
+	if !ok {
+		return nil
+	}
+	if response.DataLength == 0 {
+		parameter2 := request.Parameter2
+		if request.ID == msgModReqChanEnableState {
+			parameter2 = 0x01
+			if d.disabled {
+				parameter2 = 0x02
+			}
+		}
+		d.pending = append(d.pending, EncodeHeaderMessage(HeaderMessage{
+			ID:          response.ID,
+			Parameter1:  request.Parameter1,
+			Parameter2:  parameter2,
+			Destination: Host,
+			Source:      GenericUnit,
+		})...)
+		return nil
+	}
+	data := make([]byte, response.DataLength)
+	copy(data, d.params[request.ID])
+	switch response.ID {
+	case msgMotGetPosCounter:
+		binary.LittleEndian.PutUint32(data[2:], uint32(d.position))
+	case msgMotGetStatusBits:
+		binary.LittleEndian.PutUint32(data[2:], d.statusBits())
+	case msgMotGetDCStatusUpdate, msgMotGetStatusUpdate:
+		binary.LittleEndian.PutUint32(data[2:], uint32(d.position))
+		binary.LittleEndian.PutUint32(data[10:], d.statusBits())
+	}
+	data[0], data[1] = request.Parameter1, 0x00 // Channel ident
+	d.queueData(response.ID, data)
+	return nil
+}
+
+/*
+Applies a header only message to the simulated stage
+*/
+func (d *DryRunTransport) applyHeader(msg HeaderMessage) {
+	switch msg.ID {
+	case msgModSetChanEnableState:
+		d.disabled = msg.Parameter2 == 0x02
+	case msgMotResumeEndOfMoveMsgs:
+		d.endOfMove = true
+	case msgMotSuspendEndOfMoveMsgs:
+		d.endOfMove = false
+	case msgMotMoveHome:
+		d.position, d.homing = 0, true
+		if d.endOfMove {
+			d.pending = append(d.pending, EncodeHeaderMessage(HeaderMessage{
+				ID:          msgMotMoveHomed,
+				Parameter1:  msg.Parameter1,
+				Destination: Host,
+				Source:      GenericUnit,
+			})...)
+		}
+	case msgMotMoveAbsolute:
+		if stored := d.params[msgMotReqMoveAbsParams]; len(stored) >= 6 {
+			d.moveTo(msg.Parameter1, int32(binary.LittleEndian.Uint32(stored[2:])))
+		}
+	case msgMotMoveRelative:
+		if stored := d.params[msgMotReqMoveRelParams]; len(stored) >= 6 {
+			d.moveTo(msg.Parameter1, d.position+int32(binary.LittleEndian.Uint32(stored[2:])))
+		}
+	}
+}
+
+/*
+Applies a data message to the simulated stage. Parameters
+set are stored for the request reading them back, whose ID
+follows the one setting them
+*/
+func (d *DryRunTransport) applyData(id uint16, data []byte) {
+	if len(data) < 6 {
+		return
+	}
+	switch id {
+	case msgMotMoveAbsolute:
+		d.moveTo(data[0], int32(binary.LittleEndian.Uint32(data[2:])))
+	case msgMotMoveRelative:
+		d.moveTo(data[0], d.position+int32(binary.LittleEndian.Uint32(data[2:])))
+	default:
+		if response, ok := dryRunResponses[id+1]; ok && response.ID == id+2 {
+			d.params[id+1] = append([]byte{}, data...)
+		}
+	}
+}
+
+/*
+Moves the simulated stage at once, sending the move
+completed message if end of move messages are resumed
+*/
+func (d *DryRunTransport) moveTo(ident byte, position int32) {
+	d.position = position
+	if d.endOfMove {
+		data := make([]byte, 14)
+		data[0] = ident
+		binary.LittleEndian.PutUint32(data[2:], uint32(d.position))
+		binary.LittleEndian.PutUint32(data[10:], d.statusBits())
+		d.queueData(msgMotMoveCompleted, data)
+	}
+}
+
+/*
+Returns the status bits of the simulated stage, showing
+homing once after a home move
+*/
+func (d *DryRunTransport) statusBits() uint32 {
+	bits := dryRunStatusBits
+	if d.homing {
+		bits = bits&^0x00000400 | 0x00000200
+		d.homing = false
+	}
+	if !d.disabled {
+		bits |= 0x80000000
+	}
+	return bits
+}
+
+/*
+Queues a data message sent by the simulated controller
+*/
+func (d *DryRunTransport) queueData(id uint16, data []byte) {
+	d.pending = append(d.pending, EncodeDataMessage(DataMessage{
+		ID:          id,
+		DataLength:  uint16(len(data)),
+		Destination: Host,
+		Source:      GenericUnit,
+		Data:        data,
+	})...)
+}
+
+/*
+Returns a copy of every frame written so far
+*/
+func (d *DryRunTransport) Frames() [][]byte {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return append([][]byte{}, d.frames...)
+}
+
+/*
+Returns the frames sent so far when the controller runs in
+dry run mode, or nil otherwise
+*/
+func (k *KDC101) SentFrames() [][]byte {
+	if transport, ok := k.Communication.(*DryRunTransport); ok {
+		return transport.Frames()
+	}
+	return nil
+}
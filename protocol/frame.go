@@ -0,0 +1,128 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+Encodes a header only message into its 6 byte frame
+*/
+func EncodeHeaderMessage(msg HeaderMessage) []byte {
+	return []byte{
+		byte(msg.ID & 0x00FF),
+		byte(msg.ID >> 8),
+		msg.Parameter1,
+		msg.Parameter2,
+		byte(msg.Destination),
+		byte(msg.Source),
+	}
+}
+
+/*
+Encodes a data message into its frame: a 6 byte header
+followed by the data. The 0x80 flag marking a data packet
+is set on the destination byte
+*/
+func EncodeDataMessage(msg DataMessage) []byte {
+	frame := []byte{
+		byte(msg.ID & 0x00FF),
+		byte(msg.ID >> 8),
+		byte(msg.DataLength & 0x00FF),
+		byte(msg.DataLength >> 8),
+		byte(msg.Destination) | 0x80,
+		byte(msg.Source),
+	}
+	return append(frame, msg.Data...)
+}
+
+/*
+Decodes a header only message from its 6 byte frame
+*/
+func DecodeHeaderMessage(frame []byte) (HeaderMessage, error) {
+	if len(frame) < 6 {
+		return InvalidHeader, ErrInvalidResponseLength
+	}
+	return HeaderMessage{
+		ID:          uint16(frame[1])<<8 | uint16(frame[0]),
+		Parameter1:  frame[2],
+		Parameter2:  frame[3],
+		Destination: Endpoint(frame[4]),
+		Source:      Endpoint(frame[5]),
+	}, nil
+}
+
+/*
+Decodes a data message from its frame. If the frame is a
+header only message, a HeaderOnlyFrameError carrying it is
+returned instead
+*/
+func DecodeDataMessage(frame []byte) (DataMessage, error) {
+	if len(frame) < 6 {
+		return InvalidData, ErrInvalidResponseLength
+	}
+	if frame[4]&0x80 == 0 {
+		header, _ := DecodeHeaderMessage(frame)
+		return InvalidData, &HeaderOnlyFrameError{Header: header}
+	}
+	msg := DataMessage{
+		ID:          uint16(frame[1])<<8 | uint16(frame[0]),
+		DataLength:  uint16(frame[3])<<8 | uint16(frame[2]),
+		Destination: Endpoint(frame[4]),
+		Source:      Endpoint(frame[5]),
+	}
+	if msg.DataLength < 1 {
+		return InvalidData, fmt.Errorf("%w: data length %d", ErrInvalidResponseLength, msg.DataLength)
+	}
+	if len(frame) < 6+int(msg.DataLength) {
+		return InvalidData, ErrInvalidResponseLength
+	}
+	msg.Data = append([]byte{}, frame[6:6+int(msg.DataLength)]...)
+	return msg, nil
+}
+
+/*
+Formats a raw APT frame as a human readable line with the
+message name and ID, the destination and source endpoints
+and either the two header parameters or a hex dump of the
+data, for instance
+
+	MGMSG_MOT_MOVE_HOMED (0x0444) 0x50 -> 0x01 params 01 00
+
+Bytes captured from a logic analyzer can be pasted as is:
+short or truncated frames are reported instead of rejected
+*/
+func FormatFrame(data []byte) string {
+	if len(data) < 6 {
+		return fmt.Sprintf("short frame (%d bytes): % X", len(data), data)
+	}
+	id := uint16(data[1])<<8 | uint16(data[0])
+	name, ok := messageNames[id]
+	if !ok {
+		name = "UNKNOWN"
+	}
+	var line strings.Builder
+	fmt.Fprintf(&line, "%s (0x%04X) 0x%02X -> 0x%02X", name, id, data[4]&0x7F, data[5])
+	if data[4]&0x80 == 0 {
+		fmt.Fprintf(&line, " params %02X %02X", data[2], data[3])
+		return line.String()
+	}
+	length := int(data[3])<<8 | int(data[2])
+	payload := data[6:]
+	fmt.Fprintf(&line, " data %d bytes", length)
+	if len(payload) < length {
+		fmt.Fprintf(&line, " (truncated, %d received)", len(payload))
+	} else {
+		payload = payload[:length]
+	}
+	if len(payload) > 0 {
+		fmt.Fprintf(&line, ": % X", payload)
+	}
+	return line.String()
+}
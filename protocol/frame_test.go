@@ -0,0 +1,90 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/devicehub-go/thorlabs-kdc101/protocol"
+)
+
+func TestHeaderMessageRoundTrip(t *testing.T) {
+	msg := protocol.HeaderMessage{
+		ID:          0x0465,
+		Parameter1:  0x01,
+		Parameter2:  0x02,
+		Destination: protocol.GenericUnit,
+		Source:      protocol.Host,
+	}
+	frame := protocol.EncodeHeaderMessage(msg)
+	if expected := []byte{0x65, 0x04, 0x01, 0x02, 0x50, 0x01}; !bytes.Equal(frame, expected) {
+		t.Fatalf("encoded % X, expected % X", frame, expected)
+	}
+	decoded, err := protocol.DecodeHeaderMessage(frame)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if decoded != msg {
+		t.Errorf("decoded %+v, expected %+v", decoded, msg)
+	}
+}
+
+func TestDataMessageRoundTrip(t *testing.T) {
+	msg := protocol.DataMessage{
+		ID:          0x0453,
+		DataLength:  6,
+		Destination: protocol.GenericUnit,
+		Source:      protocol.Host,
+		Data:        []byte{0x01, 0x00, 0x78, 0x56, 0x34, 0x12},
+	}
+	frame := protocol.EncodeDataMessage(msg)
+	expected := []byte{0x53, 0x04, 0x06, 0x00, 0xD0, 0x01, 0x01, 0x00, 0x78, 0x56, 0x34, 0x12}
+	if !bytes.Equal(frame, expected) {
+		t.Fatalf("encoded % X, expected % X", frame, expected)
+	}
+	decoded, err := protocol.DecodeDataMessage(frame)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if decoded.ID != msg.ID || decoded.DataLength != msg.DataLength || decoded.Source != msg.Source ||
+		decoded.Destination != msg.Destination|0x80 || !bytes.Equal(decoded.Data, msg.Data) {
+		t.Errorf("decoded %+v, expected %+v", decoded, msg)
+	}
+}
+
+func TestDecodeInvalidFrames(t *testing.T) {
+	if _, err := protocol.DecodeHeaderMessage([]byte{0x65, 0x04}); !errors.Is(err, protocol.ErrInvalidResponseLength) {
+		t.Errorf("short header: got %v, expected ErrInvalidResponseLength", err)
+	}
+	truncated := []byte{0x91, 0x04, 0x0E, 0x00, 0x81, 0x50, 0x01, 0x00}
+	if _, err := protocol.DecodeDataMessage(truncated); !errors.Is(err, protocol.ErrInvalidResponseLength) {
+		t.Errorf("truncated data: got %v, expected ErrInvalidResponseLength", err)
+	}
+	headerOnly := []byte{0x66, 0x04, 0x01, 0x00, 0x01, 0x50}
+	_, err := protocol.DecodeDataMessage(headerOnly)
+	var frame *protocol.HeaderOnlyFrameError
+	if !errors.As(err, &frame) || frame.Header.ID != 0x0466 {
+		t.Errorf("header only frame: got %v, expected HeaderOnlyFrameError for 0x0466", err)
+	}
+}
+
+func TestFormatFrame(t *testing.T) {
+	cases := map[string][]byte{
+		"MGMSG_MOT_MOVE_HOMED (0x0444) 0x01 -> 0x50 params 01 00":                        {0x44, 0x04, 0x01, 0x00, 0x01, 0x50},
+		"MGMSG_MOT_GET_POSCOUNTER (0x0412) 0x01 -> 0x50 data 6 bytes: 01 00 10 27 00 00": {0x12, 0x04, 0x06, 0x00, 0x81, 0x50, 0x01, 0x00, 0x10, 0x27, 0x00, 0x00},
+		"UNKNOWN (0x1234) 0x01 -> 0x50 data 4 bytes (truncated, 1 received): AA":         {0x34, 0x12, 0x04, 0x00, 0x81, 0x50, 0xAA},
+		"short frame (2 bytes): 65 04":                                                   {0x65, 0x04},
+		"MGMSG_MOT_REQ_KCUBETRIGIOCONFIG (0x0524) 0x50 -> 0x01 params 01 00":             {0x24, 0x05, 0x01, 0x00, 0x50, 0x01},
+	}
+	for expected, frame := range cases {
+		if got := protocol.FormatFrame(frame); got != expected {
+			t.Errorf("got %q, expected %q", got, expected)
+		}
+	}
+}
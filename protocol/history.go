@@ -0,0 +1,67 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+import "time"
+
+type PositionSample struct {
+	Timestamp time.Time
+	Position  float64 // mm
+}
+
+/*
+Enables the position history with room for the given number
+of samples, filled by every GetDCStatusUpdate. A size of
+zero disables it, which is the default
+*/
+func (k *KDC101) SetPositionHistorySize(size int) {
+	k.historyMutex.Lock()
+	defer k.historyMutex.Unlock()
+
+	k.history = make([]PositionSample, 0, max(size, 0))
+	k.historyNext = 0
+}
+
+/*
+Returns up to the last n position samples, oldest first
+*/
+func (k *KDC101) PositionHistory(n int) []PositionSample {
+	k.historyMutex.Lock()
+	defer k.historyMutex.Unlock()
+
+	count := min(max(n, 0), len(k.history))
+	samples := make([]PositionSample, 0, count)
+	start := k.historyNext - count
+	for i := range count {
+		index := (start + i + len(k.history)) % len(k.history)
+		samples = append(samples, k.history[index])
+	}
+	return samples
+}
+
+/*
+Adds a position sample to the history, if enabled
+*/
+func (k *KDC101) recordPosition(counts int32) {
+	k.historyMutex.Lock()
+	defer k.historyMutex.Unlock()
+
+	size := cap(k.history)
+	if size == 0 {
+		return
+	}
+	sample := PositionSample{
+		Timestamp: time.Now(),
+		Position:  k.CountsToPosition(counts),
+	}
+	if len(k.history) < size {
+		k.history = append(k.history, sample)
+	} else {
+		k.history[k.historyNext] = sample
+	}
+	k.historyNext = (k.historyNext + 1) % size
+}
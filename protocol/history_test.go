@@ -0,0 +1,36 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol_test
+
+import "testing"
+
+func TestPositionHistory(t *testing.T) {
+	position := int32(0)
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		position += 34555 // About 1 mm on a MTS25-Z8
+		return statusFrame(0x0491, position, 0)
+	})
+	controller.SetPositionHistorySize(3)
+
+	for range 5 {
+		if _, err := controller.GetDCStatusUpdate(1); err != nil {
+			t.Fatalf("status update: %v", err)
+		}
+	}
+	samples := controller.PositionHistory(10)
+	if len(samples) != 3 {
+		t.Fatalf("got %d samples, expected 3", len(samples))
+	}
+	for i, expected := range []int{3, 4, 5} {
+		if int(samples[i].Position+0.5) != expected {
+			t.Errorf("sample %d: got %.3f mm, expected about %d mm", i, samples[i].Position, expected)
+		}
+	}
+	if last := controller.PositionHistory(1); len(last) != 1 || last[0] != samples[2] {
+		t.Errorf("last sample: got %v, expected %v", last, samples[2])
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+var ErrHardwareResponse = errors.New("hardware response received")
+
+/*
+Returned by the read path when the controller sends one of
+its unsolicited error messages, HW_RESPONSE (0x0080) or
+HW_RICHRESPONSE (0x0081), in place of the expected answer.
+MessageID is the message that caused it, or zero for a fault
+not related to any request; Code is the Thorlabs return code
+and Notes the text sent with the rich response
+*/
+type HardwareResponseError struct {
+	ID        uint16
+	MessageID uint16
+	Code      uint16
+	Notes     string
+
+	frame []byte
+}
+
+func (e *HardwareResponseError) Error() string {
+	if e.Notes != "" {
+		return fmt.Sprintf("%s: code %d (%s)", ErrHardwareResponse, e.Code, e.Notes)
+	}
+	return fmt.Sprintf("%s: code %d", ErrHardwareResponse, e.Code)
+}
+
+func (e *HardwareResponseError) Is(target error) bool {
+	return target == ErrHardwareResponse
+}
+
+/*
+Returns a HardwareResponseError if the frame is a hardware
+response, or nil for any other message. The code of a
+HW_RESPONSE travels in its header parameters
+*/
+func hardwareResponse(frame []byte) error {
+	if len(frame) < 6 {
+		return nil
+	}
+	id := uint16(frame[1])<<8 | uint16(frame[0])
+	switch {
+	case id == msgHwResponse:
+		return &HardwareResponseError{
+			ID:    id,
+			Code:  uint16(frame[3])<<8 | uint16(frame[2]),
+			frame: frame,
+		}
+	case id == msgHwRichResponse && len(frame) >= 10:
+		notes := frame[10:min(len(frame), 74)]
+		if end := bytes.IndexByte(notes, 0); end >= 0 {
+			notes = notes[:end]
+		}
+		return &HardwareResponseError{
+			ID:        id,
+			MessageID: uint16(frame[7])<<8 | uint16(frame[6]),
+			Code:      uint16(frame[9])<<8 | uint16(frame[8]),
+			Notes:     string(notes),
+			frame:     frame,
+		}
+	}
+	return nil
+}
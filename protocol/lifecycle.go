@@ -0,0 +1,61 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+/*
+Runs a background goroutine tracked by Close. The done
+channel is closed when Close is called, after which the
+goroutine must return promptly
+*/
+func (k *KDC101) goBackground(run func(done <-chan struct{})) {
+	k.lifeMutex.Lock()
+	if k.done == nil {
+		k.done = make(chan struct{})
+	}
+	done := k.done
+	k.workers.Add(1)
+	k.lifeMutex.Unlock()
+
+	go func() {
+		defer k.workers.Done()
+		run(done)
+	}()
+}
+
+/*
+Stops every background goroutine started by this controller
+(status subscriptions, position callbacks, streams and
+monitored moves), waits for them to exit and then closes the
+connection. Prefer it over Disconnect when any of those is
+in use, otherwise they leak and keep polling a closed port.
+Subscription and stream channels are closed, and pending
+position callbacks are dropped without firing
+*/
+func (k *KDC101) Close() error {
+	k.lifeMutex.Lock()
+	if k.done != nil {
+		close(k.done)
+		k.done = nil
+	}
+	k.lifeMutex.Unlock()
+
+	k.workers.Wait()
+	return k.Disconnect()
+}
+
+/*
+Tells whether the done channel of a background goroutine
+has been closed
+*/
+func closed(done <-chan struct{}) bool {
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
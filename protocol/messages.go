@@ -0,0 +1,184 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+/*
+APT message IDs used by the KDC101, named after the
+MGMSG_* identifiers of the Thorlabs APT protocol
+*/
+const (
+	msgHwDisconnect      = 0x0002
+	msgHwReqInfo         = 0x0005
+	msgHwGetInfo         = 0x0006
+	msgHwStartUpdateMsgs = 0x0011
+	msgHwStopUpdateMsgs  = 0x0012
+	msgHwResponse        = 0x0080
+	msgHwRichResponse    = 0x0081
+
+	msgModSetChanEnableState = 0x0210
+	msgModReqChanEnableState = 0x0211
+	msgModGetChanEnableState = 0x0212
+	msgModIdentify           = 0x0223
+
+	msgMotReqPosCounter = 0x0411
+	msgMotGetPosCounter = 0x0412
+
+	msgMotSetVelParams = 0x0413
+	msgMotReqVelParams = 0x0414
+	msgMotGetVelParams = 0x0415
+
+	msgMotSetJogParams = 0x0416
+	msgMotReqJogParams = 0x0417
+	msgMotGetJogParams = 0x0418
+
+	msgMotSetLimSwitchParams = 0x0423
+	msgMotReqLimSwitchParams = 0x0424
+	msgMotGetLimSwitchParams = 0x0425
+
+	msgMotReqStatusBits = 0x0429
+	msgMotGetStatusBits = 0x042A
+
+	msgMotSetGenMoveParams = 0x043A
+	msgMotReqGenMoveParams = 0x043B
+	msgMotGetGenMoveParams = 0x043C
+
+	msgMotSetHomeParams = 0x0440
+	msgMotReqHomeParams = 0x0441
+	msgMotGetHomeParams = 0x0442
+	msgMotMoveHome      = 0x0443
+	msgMotMoveHomed     = 0x0444
+
+	msgMotSetMoveRelParams = 0x0445
+	msgMotReqMoveRelParams = 0x0446
+	msgMotGetMoveRelParams = 0x0447
+
+	// MGMSG_MOT_MOVE_RELATIVE is overloaded: sent header only
+	// it moves by the distance stored with 0x0445, sent as a
+	// data packet it carries the distance itself. The frames
+	// are told apart by the 0x80 data flag of the destination
+	// byte, and neither form is answered by the controller
+	msgMotMoveRelative         = 0x0448
+	msgMotMoveRelativeDistance = msgMotMoveRelative
+
+	msgMotSetMoveAbsParams = 0x0450
+	msgMotReqMoveAbsParams = 0x0451
+	msgMotGetMoveAbsParams = 0x0452
+
+	// MGMSG_MOT_MOVE_ABSOLUTE is overloaded the same way
+	msgMotMoveAbsolute         = 0x0453
+	msgMotMoveAbsolutePosition = msgMotMoveAbsolute
+
+	msgMotMoveVelocity  = 0x0457
+	msgMotMoveCompleted = 0x0464
+	msgMotMoveStop      = 0x0465
+	msgMotMoveStopped   = 0x0466
+	msgMotMoveJog       = 0x046A
+
+	msgMotSuspendEndOfMoveMsgs = 0x046B
+	msgMotResumeEndOfMoveMsgs  = 0x046C
+
+	msgMotReqStatusUpdate = 0x0480
+	msgMotGetStatusUpdate = 0x0481
+
+	msgMotSetBowIndex = 0x04F4
+	msgMotReqBowIndex = 0x04F5
+	msgMotGetBowIndex = 0x04F6
+
+	msgMotReqKCubeMMIParams     = 0x0521
+	msgMotGetKCubeMMIParams     = 0x0522
+	msgMotReqKCubeTrigIOConfig  = 0x0524
+	msgMotGetKCubeTrigIOConfig  = 0x0525
+	msgMotReqKCubePosTrigParams = 0x0527
+	msgMotGetKCubePosTrigParams = 0x0528
+
+	msgMotReqDCStatusUpdate = 0x0490
+	msgMotGetDCStatusUpdate = 0x0491
+	msgMotAckDCStatusUpdate = 0x0492
+)
+
+/*
+MGMSG_* names of the message IDs above, used by FormatFrame
+*/
+var messageNames = map[uint16]string{
+	msgHwDisconnect:      "MGMSG_HW_DISCONNECT",
+	msgHwReqInfo:         "MGMSG_HW_REQ_INFO",
+	msgHwGetInfo:         "MGMSG_HW_GET_INFO",
+	msgHwStartUpdateMsgs: "MGMSG_HW_START_UPDATEMSGS",
+	msgHwStopUpdateMsgs:  "MGMSG_HW_STOP_UPDATEMSGS",
+	msgHwResponse:        "MGMSG_HW_RESPONSE",
+	msgHwRichResponse:    "MGMSG_HW_RICHRESPONSE",
+
+	msgModSetChanEnableState: "MGMSG_MOD_SET_CHANENABLESTATE",
+	msgModReqChanEnableState: "MGMSG_MOD_REQ_CHANENABLESTATE",
+	msgModGetChanEnableState: "MGMSG_MOD_GET_CHANENABLESTATE",
+	msgModIdentify:           "MGMSG_MOD_IDENTIFY",
+
+	msgMotReqPosCounter: "MGMSG_MOT_REQ_POSCOUNTER",
+	msgMotGetPosCounter: "MGMSG_MOT_GET_POSCOUNTER",
+
+	msgMotSetVelParams: "MGMSG_MOT_SET_VELPARAMS",
+	msgMotReqVelParams: "MGMSG_MOT_REQ_VELPARAMS",
+	msgMotGetVelParams: "MGMSG_MOT_GET_VELPARAMS",
+
+	msgMotSetJogParams: "MGMSG_MOT_SET_JOGPARAMS",
+	msgMotReqJogParams: "MGMSG_MOT_REQ_JOGPARAMS",
+	msgMotGetJogParams: "MGMSG_MOT_GET_JOGPARAMS",
+
+	msgMotSetLimSwitchParams: "MGMSG_MOT_SET_LIMSWITCHPARAMS",
+	msgMotReqLimSwitchParams: "MGMSG_MOT_REQ_LIMSWITCHPARAMS",
+	msgMotGetLimSwitchParams: "MGMSG_MOT_GET_LIMSWITCHPARAMS",
+
+	msgMotReqStatusBits: "MGMSG_MOT_REQ_STATUSBITS",
+	msgMotGetStatusBits: "MGMSG_MOT_GET_STATUSBITS",
+
+	msgMotSetGenMoveParams: "MGMSG_MOT_SET_GENMOVEPARAMS",
+	msgMotReqGenMoveParams: "MGMSG_MOT_REQ_GENMOVEPARAMS",
+	msgMotGetGenMoveParams: "MGMSG_MOT_GET_GENMOVEPARAMS",
+
+	msgMotSetHomeParams: "MGMSG_MOT_SET_HOMEPARAMS",
+	msgMotReqHomeParams: "MGMSG_MOT_REQ_HOMEPARAMS",
+	msgMotGetHomeParams: "MGMSG_MOT_GET_HOMEPARAMS",
+	msgMotMoveHome:      "MGMSG_MOT_MOVE_HOME",
+	msgMotMoveHomed:     "MGMSG_MOT_MOVE_HOMED",
+
+	msgMotSetMoveRelParams: "MGMSG_MOT_SET_MOVERELPARAMS",
+	msgMotReqMoveRelParams: "MGMSG_MOT_REQ_MOVERELPARAMS",
+	msgMotGetMoveRelParams: "MGMSG_MOT_GET_MOVERELPARAMS",
+	msgMotMoveRelative:     "MGMSG_MOT_MOVE_RELATIVE",
+
+	msgMotSetMoveAbsParams: "MGMSG_MOT_SET_MOVEABSPARAMS",
+	msgMotReqMoveAbsParams: "MGMSG_MOT_REQ_MOVEABSPARAMS",
+	msgMotGetMoveAbsParams: "MGMSG_MOT_GET_MOVEABSPARAMS",
+	msgMotMoveAbsolute:     "MGMSG_MOT_MOVE_ABSOLUTE",
+
+	msgMotMoveVelocity:  "MGMSG_MOT_MOVE_VELOCITY",
+	msgMotMoveCompleted: "MGMSG_MOT_MOVE_COMPLETED",
+	msgMotMoveStop:      "MGMSG_MOT_MOVE_STOP",
+	msgMotMoveStopped:   "MGMSG_MOT_MOVE_STOPPED",
+	msgMotMoveJog:       "MGMSG_MOT_MOVE_JOG",
+
+	msgMotSuspendEndOfMoveMsgs: "MGMSG_MOT_SUSPEND_ENDOFMOVEMSGS",
+	msgMotResumeEndOfMoveMsgs:  "MGMSG_MOT_RESUME_ENDOFMOVEMSGS",
+
+	msgMotReqStatusUpdate: "MGMSG_MOT_REQ_STATUSUPDATE",
+	msgMotGetStatusUpdate: "MGMSG_MOT_GET_STATUSUPDATE",
+
+	msgMotSetBowIndex: "MGMSG_MOT_SET_BOWINDEX",
+	msgMotReqBowIndex: "MGMSG_MOT_REQ_BOWINDEX",
+	msgMotGetBowIndex: "MGMSG_MOT_GET_BOWINDEX",
+
+	msgMotReqKCubeMMIParams:     "MGMSG_MOT_REQ_KCUBEMMIPARAMS",
+	msgMotGetKCubeMMIParams:     "MGMSG_MOT_GET_KCUBEMMIPARAMS",
+	msgMotReqKCubeTrigIOConfig:  "MGMSG_MOT_REQ_KCUBETRIGIOCONFIG",
+	msgMotGetKCubeTrigIOConfig:  "MGMSG_MOT_GET_KCUBETRIGIOCONFIG",
+	msgMotReqKCubePosTrigParams: "MGMSG_MOT_REQ_KCUBEPOSTRIGPARAMS",
+	msgMotGetKCubePosTrigParams: "MGMSG_MOT_GET_KCUBEPOSTRIGPARAMS",
+
+	msgMotReqDCStatusUpdate: "MGMSG_MOT_REQ_DCSTATUSUPDATE",
+	msgMotGetDCStatusUpdate: "MGMSG_MOT_GET_DCSTATUSUPDATE",
+	msgMotAckDCStatusUpdate: "MGMSG_MOT_ACK_DCSTATUSUPDATE",
+}
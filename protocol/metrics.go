@@ -0,0 +1,67 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+/*
+Counters of the frames exchanged with the controller since
+the KDC101 was created
+*/
+type MetricsSnapshot struct {
+	CommandsSent      uint64
+	ResponsesReceived uint64
+	ReadErrors        uint64
+	WriteErrors       uint64
+}
+
+type metrics struct {
+	commandsSent      atomic.Uint64
+	responsesReceived atomic.Uint64
+	readErrors        atomic.Uint64
+	writeErrors       atomic.Uint64
+}
+
+/*
+Returns the current value of the frame counters. It is safe
+to call concurrently with any other method
+*/
+func (k *KDC101) Metrics() MetricsSnapshot {
+	return MetricsSnapshot{
+		CommandsSent:      k.metrics.commandsSent.Load(),
+		ResponsesReceived: k.metrics.responsesReceived.Load(),
+		ReadErrors:        k.metrics.readErrors.Load(),
+		WriteErrors:       k.metrics.writeErrors.Load(),
+	}
+}
+
+/*
+Counts a frame write
+*/
+func (k *KDC101) countWrite(err error) {
+	if err != nil {
+		k.metrics.writeErrors.Add(1)
+		return
+	}
+	k.metrics.commandsSent.Add(1)
+}
+
+/*
+Counts a frame read. Header only frames received in place
+of a data packet are still responses, so they are not
+counted as errors
+*/
+func (k *KDC101) countRead(err error) {
+	if err != nil && !errors.Is(err, ErrHeaderOnlyFrame) {
+		k.metrics.readErrors.Add(1)
+		return
+	}
+	k.metrics.responsesReceived.Add(1)
+}
@@ -1,13 +1,17 @@
 /*
 Author: Leonardo Rossi Leao
 Created at: September 26th, 2025
-Last update: September 26th, 2025
+Last update: October 16th, 2026
 */
 
 package protocol
 
 import (
-	"github.com/devicehub-go/thorlabs-kdc101/internal/utils"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
 )
 
 type DCStatusUpdate struct {
@@ -33,7 +37,7 @@ type DCStatusBits struct {
 	IsInitializing   bool
 	IsTracking       bool
 	IsSettled        bool
-	PositionError    bool
+	PositionError    bool // Following error over the limit; the error itself is not reported
 	InstructionError bool
 	Interlock        bool
 	OverTemperature  bool
@@ -46,27 +50,411 @@ type DCStatusBits struct {
 	PowerOk          bool
 	IsActive         bool
 	Error            bool
-	IsEnabled        bool
+	IsEnabled        bool // Servo loop active; not the channel enable state of KDC101.IsEnabled
 }
 
 type DCStatusUpdateSI struct {
 	Channel    uint16
-	Position   float64
-	Velocity   float64
-	Current    float64
+	Position   float64 // mm
+	Velocity   float64 // mm/s
+	Current    float64 // mA
 	StatusBits DCStatusBits
+	Timestamp  time.Time // Host time of the conversion
 }
 
 /*
-Request a status update for the specified DC motor channel
+Tells whether the status shows the motor moving, jogging or
+homing
+*/
+func inMotion(bits DCStatusBits) bool {
+	return bits.InMotionCW || bits.InMotionCCW || bits.JoggingCW || bits.JoggingCCW || bits.IsHoming
+}
+
+var ErrMoveStopped = errors.New("move stopped message received")
+var ErrBusVoltageFault = errors.New("bus voltage fault")
+var ErrBusCurrentFault = errors.New("bus current fault")
+var ErrPowerNotOk = errors.New("power supply not ok")
+var ErrMotorFault = errors.New("motor fault")
+var ErrNotHomed = errors.New("stage not homed")
+
+/*
+Request a status update for the specified DC motor channel.
+If the controller sends its unsolicited move stopped message
+instead of the update, ErrMoveStopped is returned. Its move
+completed message carries the same status block as the
+update, so it is returned as the update
 */
 func (k *KDC101) GetDCStatusUpdate(channel uint8) (DCStatusUpdate, error) {
-	if channel != 1 {
-		return DCStatusUpdate{}, ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return DCStatusUpdate{}, err
+	}
+	msg := HeaderMessage{
+		ID:          msgMotReqDCStatusUpdate,
+		Parameter1:  ident,
+		Parameter2:  0x00,
+		Destination: GenericUnit,
+		Source:      Host,
+	}
+	response, err := k.RequestData(msg)
+	if err != nil {
+		return DCStatusUpdate{}, err
+	}
+	if response.ID == msgMotMoveStopped {
+		return DCStatusUpdate{}, ErrMoveStopped
+	}
+	if response.ID != msgMotMoveCompleted {
+		if err := expectResponse(response, msgMotGetDCStatusUpdate); err != nil {
+			return DCStatusUpdate{}, err
+		}
+	}
+	update, err := k.parseDCStatusUpdate(response.Data)
+	if err != nil {
+		return DCStatusUpdate{}, err
+	}
+	k.recordPosition(update.Position)
+	return update, nil
+}
+
+/*
+Requests a DC status update for the specified channel and
+converts it to SI units
+*/
+func (k *KDC101) GetStatusUpdateSI(channel uint8) (DCStatusUpdateSI, error) {
+	status, err := k.GetDCStatusUpdate(channel)
+	if err != nil {
+		return DCStatusUpdateSI{}, err
+	}
+	return k.DCStatusUpdateToSI(status), nil
+}
+
+/*
+Gets the current position of the specified channel
+in millimeters, or in degrees wrapped as set by AngleWrap
+for rotary stages
+*/
+func (k *KDC101) GetPosition(channel uint8) (float64, error) {
+	position, err := k.getRawPosition(channel)
+	if err != nil {
+		return 0, err
+	}
+	return k.wrapAngle(position), nil
+}
+
+/*
+Gets the position without wrapping, e.g. the cumulative
+degrees of a rotary stage
+*/
+func (k *KDC101) getRawPosition(channel uint8) (float64, error) {
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return 0, err
+	}
+	response, err := k.RequestData(HeaderMessage{
+		ID:          msgMotReqPosCounter,
+		Parameter1:  ident,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := expectResponse(response, msgMotGetPosCounter); err != nil {
+		return 0, err
+	}
+	data := response.Data
+	if len(data) < 6 {
+		return 0, ErrInvalidResponseLength
+	}
+	return k.CountsToPosition(k.codec().BytesToLong(data[2:6])), nil
+}
+
+/*
+Returns true if the stage is at rest within tolerance of the
+target position, both in millimeters. A stage passing
+through the target while moving, or not settled yet, is
+not considered at position
+*/
+func (k *KDC101) IsAtPosition(channel uint8, target, tolerance float64) (bool, error) {
+	status, err := k.GetDCStatusUpdate(channel)
+	if err != nil {
+		return false, err
+	}
+	bits := k.ParseDCStatusBits(status.StatusBits)
+	moving := bits.InMotionCW || bits.InMotionCCW || bits.JoggingCW || bits.JoggingCCW
+	if moving || !bits.IsSettled {
+		return false, nil
+	}
+	return math.Abs(k.CountsToPosition(status.Position)-target) <= tolerance, nil
+}
+
+/*
+Returns how far the stage ended up from the target of the
+last absolute move, actual minus commanded, in millimeters
+(degrees for rotary stages). The target is the position
+given to the last MoveAbsolutePosition, or the absolute
+move parameter stored on the device if the move used it.
+Meant to be read once the move has completed
+*/
+func (k *KDC101) PositionDelta(channel uint8) (float64, error) {
+	var target float64
+	if k.absoluteTarget != nil {
+		target = k.CountsToPosition(*k.absoluteTarget)
+	} else {
+		stored, err := k.GetAbsoluteMoveDistance(channel)
+		if err != nil {
+			return 0, err
+		}
+		target = stored
+	}
+	position, err := k.getRawPosition(channel)
+	if err != nil {
+		return 0, err
+	}
+	return position - target, nil
+}
+
+/*
+Status bits cached by GetCachedStatus for a channel
+*/
+type cachedStatus struct {
+	bits DCStatusBits
+	at   time.Time
+}
+
+/*
+Returns the status bits of the channel read less than
+maxAge ago, or reads them again from the device when the
+cached ones are older. Safe to call from several
+goroutines; the cache is not locked while reading, so
+concurrent callers missing it may each read the status
+*/
+func (k *KDC101) GetCachedStatus(channel uint8, maxAge time.Duration) (DCStatusBits, error) {
+	k.cacheMutex.Lock()
+	cached, ok := k.cachedStatus[channel]
+	k.cacheMutex.Unlock()
+	if ok && time.Since(cached.at) < maxAge {
+		return cached.bits, nil
+	}
+
+	status, err := k.GetDCStatusUpdate(channel)
+	if err != nil {
+		return DCStatusBits{}, err
+	}
+	bits := k.ParseDCStatusBits(status.StatusBits)
+	k.cacheMutex.Lock()
+	defer k.cacheMutex.Unlock()
+	if k.cachedStatus == nil {
+		k.cachedStatus = make(map[uint8]cachedStatus)
+	}
+	k.cachedStatus[channel] = cachedStatus{bits: bits, at: time.Now()}
+	return bits, nil
+}
+
+/*
+Returns true if the homing of the specified channel has
+completed, read from the DC status bits
+*/
+func (k *KDC101) IsHomed(channel uint8) (bool, error) {
+	status, err := k.GetDCStatusUpdate(channel)
+	if err != nil {
+		return false, err
+	}
+	return k.ParseDCStatusBits(status.StatusBits).IsHomed, nil
+}
+
+/*
+Returns where the controller placed home, measured from the
+home limit switch in millimeters: positive when homing runs
+in reverse, so home lies forward of the switch, and
+negative otherwise. Homing zeroes the position counter at
+home, so in stage coordinates home is always at 0; this is
+the offset from the hardware reference that repeatability
+checks compare. ErrNotHomed is returned before homing
+*/
+func (k *KDC101) GetHomePosition(channel uint8) (float64, error) {
+	homed, err := k.IsHomed(channel)
+	if err != nil {
+		return 0, err
+	}
+	if !homed {
+		return 0, ErrNotHomed
+	}
+	params, err := k.GetHomeParameters(channel)
+	if err != nil {
+		return 0, err
+	}
+	if Direction(params.Direction) == Forward {
+		return -params.OffsetDistance, nil
+	}
+	return params.OffsetDistance, nil
+}
+
+/*
+Returns true if the specified channel is performing a
+homing move, read from the DC status bits
+*/
+func (k *KDC101) IsHoming(channel uint8) (bool, error) {
+	status, err := k.GetDCStatusUpdate(channel)
+	if err != nil {
+		return false, err
+	}
+	return k.ParseDCStatusBits(status.StatusBits).IsHoming, nil
+}
+
+/*
+Gets the motor current of the specified channel in milliamps
+*/
+func (k *KDC101) GetMotorCurrent(channel uint8) (float64, error) {
+	status, err := k.GetDCStatusUpdate(channel)
+	if err != nil {
+		return 0, err
+	}
+	return k.CountsToCurrent(status.Current), nil
+}
+
+/*
+Reads the status of the specified channel and returns the
+power faults it reports joined in a single error, or nil if
+there are none. The KDC101 does not report its bus voltage,
+so only the fault bits are available
+*/
+func (k *KDC101) CheckPowerFaults(channel uint8) error {
+	status, err := k.GetDCStatusUpdate(channel)
+	if err != nil {
+		return err
+	}
+	bits := k.ParseDCStatusBits(status.StatusBits)
+	var faults []error
+	if bits.BusVoltageFault {
+		faults = append(faults, ErrBusVoltageFault)
+	}
+	if bits.BusCurrentFault {
+		faults = append(faults, ErrBusCurrentFault)
+	}
+	if !bits.PowerOk {
+		faults = append(faults, ErrPowerNotOk)
+	}
+	return errors.Join(faults...)
+}
+
+/*
+Returns the names of the fault conditions set in the status
+bits, or nil if there are none
+*/
+func activeFaults(bits DCStatusBits) []string {
+	var faults []string
+	for _, fault := range []struct {
+		set  bool
+		name string
+	}{
+		{bits.PositionError, "position error"},
+		{bits.Interlock, "interlock"},
+		{bits.OverTemperature, "over temperature"},
+		{bits.BusVoltageFault, "bus voltage fault"},
+		{bits.CommutationError, "commutation error"},
+		{bits.Overload, "overload"},
+		{bits.EncoderFault, "encoder fault"},
+		{bits.OverCurrent, "over current"},
+		{bits.BusCurrentFault, "bus current fault"},
+		{bits.Error, "error"},
+	} {
+		if fault.set {
+			faults = append(faults, fault.name)
+		}
+	}
+	return faults
+}
+
+/*
+Reads the status of the specified channel and returns
+ErrMotorFault listing the active faults, if any
+*/
+func (k *KDC101) checkFaults(channel uint8) error {
+	status, err := k.GetDCStatusUpdate(channel)
+	if err != nil {
+		return err
+	}
+	if faults := activeFaults(k.ParseDCStatusBits(status.StatusBits)); len(faults) > 0 {
+		return fmt.Errorf("%w: %s", ErrMotorFault, strings.Join(faults, ", "))
+	}
+	return nil
+}
+
+/*
+Status of a channel packaged for monitoring: the converted
+status update, and whether a fault is active with the names
+of the active faults (empty when there are none)
+*/
+type Health struct {
+	DCStatusUpdateSI
+	HasFault bool
+	Faults   []string
+}
+
+/*
+Reads the status of the specified channel in one request and
+summarizes its fault bits, for monitoring panels
+*/
+func (k *KDC101) GetHealth(channel uint8) (Health, error) {
+	status, err := k.GetStatusUpdateSI(channel)
+	if err != nil {
+		return Health{}, err
+	}
+	faults := activeFaults(status.StatusBits)
+	return Health{
+		DCStatusUpdateSI: status,
+		HasFault:         len(faults) > 0,
+		Faults:           append([]string{}, faults...),
+	}, nil
+}
+
+/*
+Requests only the status bits of the specified channel with
+the short status bits message, which is lighter than a full
+status update for high frequency flag polling. Falls back to
+the DC status update if the controller does not answer it.
+Decode the result with ParseDCStatusBits
+*/
+func (k *KDC101) GetStatusBitsRaw(channel uint8) (uint32, error) {
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return 0, err
+	}
+	response, err := k.RequestData(HeaderMessage{
+		ID:          msgMotReqStatusBits,
+		Parameter1:  ident,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+	if err == nil && response.ID == msgMotGetStatusBits && len(response.Data) >= 6 {
+		return k.codec().BytesToDword(response.Data[2:6]), nil
+	}
+	if err != nil && !errors.Is(err, ErrInvalidResponseLength) {
+		return 0, err
+	}
+	status, err := k.GetDCStatusUpdate(channel)
+	if err != nil {
+		return 0, err
+	}
+	return status.StatusBits, nil
+}
+
+/*
+Request a status update for the specified motor channel
+using the generic motor status message. Unlike the DC
+variant it does not report velocity nor motor current, so
+those fields are left zeroed. Some firmware revisions only
+answer one of the two requests.
+*/
+func (k *KDC101) GetStatusUpdate(channel uint8) (DCStatusUpdate, error) {
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return DCStatusUpdate{}, err
 	}
 	msg := HeaderMessage{
-		ID:          0x0490,
-		Parameter1:  byte(1 << (channel - 1)),
+		ID:          msgMotReqStatusUpdate,
+		Parameter1:  ident,
 		Parameter2:  0x00,
 		Destination: GenericUnit,
 		Source:      Host,
@@ -75,13 +463,33 @@ func (k *KDC101) GetDCStatusUpdate(channel uint8) (DCStatusUpdate, error) {
 	if err != nil {
 		return DCStatusUpdate{}, err
 	}
+	if err := expectResponse(response, msgMotGetStatusUpdate); err != nil {
+		return DCStatusUpdate{}, err
+	}
 	data := response.Data
+	if len(data) < 14 {
+		return DCStatusUpdate{}, ErrInvalidResponseLength
+	}
+	return DCStatusUpdate{
+		Channel:    k.codec().BytesToWord(data[0:2]),
+		Position:   k.codec().BytesToLong(data[2:6]),
+		StatusBits: k.codec().BytesToDword(data[10:14]),
+	}, nil
+}
+
+/*
+Decodes the data block of a DC status update message
+*/
+func (k *KDC101) parseDCStatusUpdate(data []byte) (DCStatusUpdate, error) {
+	if len(data) < 14 {
+		return DCStatusUpdate{}, ErrInvalidResponseLength
+	}
 	return DCStatusUpdate{
-		Channel:    utils.BytesToWord(data[0:2]),
-		Position:   utils.BytesToLong(data[2:6]),
-		Velocity:   utils.BytesToWord(data[6:8]),
-		Current:    utils.BytesToShort(data[8:10]),
-		StatusBits: utils.BytesToDword(data[10:14]),
+		Channel:    k.codec().BytesToWord(data[0:2]),
+		Position:   k.codec().BytesToLong(data[2:6]),
+		Velocity:   k.codec().BytesToWord(data[6:8]),
+		Current:    k.codec().BytesToShort(data[8:10]),
+		StatusBits: k.codec().BytesToDword(data[10:14]),
 	}, nil
 }
 
@@ -92,10 +500,11 @@ and stage type
 func (k *KDC101) DCStatusUpdateToSI(update DCStatusUpdate) DCStatusUpdateSI {
 	return DCStatusUpdateSI{
 		Channel:  update.Channel,
-		Position: k.CountsToPosition(update.Position),
+		Position: k.wrapAngle(k.CountsToPosition(update.Position)),
 		Velocity: k.CountsToVelocity(uint32(update.Velocity)),
-		Current:  float64(update.Current),
+		Current:  k.CountsToCurrent(update.Current),
 		StatusBits: k.ParseDCStatusBits(update.StatusBits),
+		Timestamp: time.Now(),
 	}
 }
 
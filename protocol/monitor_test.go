@@ -0,0 +1,86 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol_test
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPositionDelta(t *testing.T) {
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		if frame[0] != 0x11 {
+			return nil
+		}
+		return []byte{0x12, 0x04, 0x06, 0x00, 0x81, 0x50, 0x01, 0x00, 0x64, 0x00, 0x00, 0x00} // 100 counts
+	})
+
+	if err := controller.MoveAbsoluteCounts(1, 90); err != nil {
+		t.Fatalf("MoveAbsoluteCounts: %v", err)
+	}
+	delta, err := controller.PositionDelta(1)
+	if err != nil {
+		t.Fatalf("PositionDelta: %v", err)
+	}
+	if expected := controller.CountsToPosition(10); math.Abs(delta-expected) > 1e-12 {
+		t.Errorf("got delta %v, expected %v", delta, expected)
+	}
+}
+
+func TestGetCachedStatusPerChannel(t *testing.T) {
+	requests := 0
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		if frame[0] != 0x90 {
+			return nil
+		}
+		requests++
+		var bits uint32
+		if frame[2] == 0x02 {
+			bits = 0x400 // Only channel 2 homed
+		}
+		return statusFrame(0x0491, 0, bits)
+	})
+	controller.MaxChannels = 2
+
+	for range 2 {
+		first, err := controller.GetCachedStatus(1, time.Minute)
+		if err != nil {
+			t.Fatalf("GetCachedStatus channel 1: %v", err)
+		}
+		second, err := controller.GetCachedStatus(2, time.Minute)
+		if err != nil {
+			t.Fatalf("GetCachedStatus channel 2: %v", err)
+		}
+		if first.IsHomed || !second.IsHomed {
+			t.Errorf("got homed %t and %t, expected only channel 2 homed", first.IsHomed, second.IsHomed)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("got %d status requests, expected one per channel", requests)
+	}
+}
+
+func TestGetHealth(t *testing.T) {
+	var bits uint32
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		return statusFrame(0x0491, 10000, bits)
+	})
+
+	health, err := controller.GetHealth(1)
+	if err != nil || health.HasFault || health.Faults == nil || len(health.Faults) != 0 {
+		t.Errorf("got %+v, %v, expected a healthy status with no faults", health, err)
+	}
+	bits = 0x04000000 // Over current
+	health, err = controller.GetHealth(1)
+	if err != nil || !health.HasFault || len(health.Faults) != 1 || health.Faults[0] != "over current" {
+		t.Errorf("got %+v, %v, expected the over current fault", health, err)
+	}
+	if health.Position != controller.CountsToPosition(10000) {
+		t.Errorf("got position %v, expected %v", health.Position, controller.CountsToPosition(10000))
+	}
+}
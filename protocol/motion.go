@@ -0,0 +1,600 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+const (
+	MotionPollInterval = 50 * time.Millisecond
+	DefaultMoveTimeout = 60 * time.Second
+
+	// Time allowed for a whole frame to arrive on transports
+	// that do not expose their read timeout
+	DefaultFrameTimeout = 500 * time.Millisecond
+
+	// Added to the estimated travel time when a blocking move
+	// derives its timeout, covering settling and polling
+	MoveTimeoutMargin = 2 * time.Second
+
+	// Time HomeAndWait gives a stage already homed to show
+	// that homing started before taking it as completed
+	HomeStartGrace = 500 * time.Millisecond
+)
+
+var ErrMoveTimeout = fmt.Errorf("%w waiting for the motor to stop", ErrTimeout)
+var ErrHomingFailed = fmt.Errorf("homing stopped before completing")
+var ErrNotStable = fmt.Errorf("%w waiting for the position to be stable", ErrTimeout)
+var ErrInvalidVelocityProfile = fmt.Errorf("velocity profile must have positive velocity and acceleration")
+var ErrStoppedBeforePosition = fmt.Errorf("motor stopped before reaching the stop position")
+
+/*
+Returned by MoveSequence with the index of the position whose
+move failed
+*/
+type SequenceError struct {
+	Index int
+	Err   error
+}
+
+func (e *SequenceError) Error() string {
+	return fmt.Sprintf("move %d of sequence: %v", e.Index, e.Err)
+}
+
+func (e *SequenceError) Unwrap() error {
+	return e.Err
+}
+
+/*
+Starts a continuous move in the specified direction and
+streams the position on the returned channel every interval,
+in the units of GetPosition (degrees wrapped as set by
+AngleWrap on rotary stages). When the context is cancelled, Close is
+called or a status read fails, the motor is stopped with a
+profiled stop and
+the channel is closed, even if the consumer stopped reading
+*/
+func (k *KDC101) MoveContinuousAndMonitor(ctx context.Context, channel uint8, direction Direction, interval time.Duration) (<-chan float64, error) {
+	if err := k.MoveContinuous(channel, direction); err != nil {
+		return nil, err
+	}
+	positions := make(chan float64)
+	k.goBackground(func(done <-chan struct{}) {
+		defer close(positions)
+		defer k.Stop(channel, Soft)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			status, err := k.GetDCStatusUpdate(channel)
+			if err != nil {
+				return
+			}
+			select {
+			case positions <- k.wrapAngle(k.CountsToPosition(status.Position)):
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			}
+		}
+	})
+	return positions, nil
+}
+
+/*
+Moves at constant velocity in the specified direction and
+issues a profiled stop once the stage passes the stop
+position. The position is compared without AngleWrap, so
+on rotary stages the stop position is in cumulative degrees
+and may lie past a full turn. Nothing is moved if the stage
+is already past it. ErrStoppedBeforePosition is returned if
+the motor stops first, e.g. on a limit switch, and the
+motor is stopped if the context is cancelled
+*/
+func (k *KDC101) MoveVelocityUntil(ctx context.Context, channel uint8, direction Direction, stopPosition float64) error {
+	if err := validateDirection(direction); err != nil {
+		return err
+	}
+	passed := func(position float64) bool {
+		if direction == Forward {
+			return position >= stopPosition
+		}
+		return position <= stopPosition
+	}
+	position, err := k.getRawPosition(channel)
+	if err != nil {
+		return err
+	}
+	if passed(position) {
+		return nil
+	}
+	if err := k.MoveContinuous(channel, direction); err != nil {
+		return err
+	}
+	defer k.Stop(channel, Soft)
+
+	ticker := time.NewTicker(MotionPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		status, err := k.GetDCStatusUpdate(channel)
+		if errors.Is(err, ErrHeaderOnlyFrame) {
+			continue // Unsolicited event received instead of the update
+		}
+		if errors.Is(err, ErrMoveStopped) {
+			return fmt.Errorf("%w: %w", ErrStoppedBeforePosition, err)
+		}
+		if err != nil {
+			return err
+		}
+		if passed(k.CountsToPosition(status.Position)) {
+			return nil
+		}
+		bits := k.ParseDCStatusBits(status.StatusBits)
+		if bits.CWHardLimit || bits.CCWHardLimit {
+			return fmt.Errorf("%w: limit switch reached", ErrStoppedBeforePosition)
+		}
+		if !inMotion(bits) {
+			return ErrStoppedBeforePosition
+		}
+	}
+}
+
+/*
+Moves at constant velocity in the specified direction for
+the given duration, then issues a profiled stop and waits
+until the motor has come to rest. Cancelling the context
+ends the move early. The stop is sent whatever happens once
+the move has started
+*/
+func (k *KDC101) MoveForDuration(ctx context.Context, channel uint8, direction Direction, d time.Duration) (err error) {
+	if err := k.MoveContinuous(channel, direction); err != nil {
+		return err
+	}
+	defer func() {
+		if stopErr := k.StopAndWait(channel, Soft, DefaultMoveTimeout); stopErr != nil {
+			err = errors.Join(err, stopErr)
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+/*
+Polls the status of the specified channel every interval
+until the predicate holds for the status bits, returning
+ErrMoveTimeout if the timeout expires first. The first poll
+is delayed by one interval so a move that was just issued
+has time to show up in the status
+*/
+func (k *KDC101) WaitFor(channel uint8, predicate func(DCStatusBits) bool, interval, timeout time.Duration) error {
+	return k.waitFor(context.Background(), channel, predicate, interval, timeout)
+}
+
+/*
+Implements WaitFor. If the context is cancelled, a profiled
+stop is sent to the motor and the context error is returned
+*/
+func (k *KDC101) waitFor(ctx context.Context, channel uint8, predicate func(DCStatusBits) bool, interval, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		select {
+		case <-ctx.Done():
+			if err := k.Stop(channel, Soft); err != nil {
+				return errors.Join(ctx.Err(), err)
+			}
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		status, err := k.GetDCStatusUpdate(channel)
+		if errors.Is(err, ErrHeaderOnlyFrame) {
+			continue // Unsolicited event received instead of the update
+		}
+		if err != nil {
+			return err
+		}
+		if predicate(k.ParseDCStatusBits(status.StatusBits)) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrMoveTimeout
+		}
+	}
+}
+
+/*
+Polls the position every interval until the given number of
+consecutive samples stay within epsilon of the first one,
+confirming the stage has settled without trusting the
+settled status bit alone. ErrNotStable is returned if the
+timeout expires first
+*/
+func (k *KDC101) WaitForStable(channel uint8, epsilon float64, samples int, interval, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	reference, err := k.getRawPosition(channel)
+	if err != nil {
+		return err
+	}
+	stable := 1
+	for stable < samples {
+		if time.Now().After(deadline) {
+			return ErrNotStable
+		}
+		time.Sleep(interval)
+		position, err := k.getRawPosition(channel)
+		if err != nil {
+			return err
+		}
+		if math.Abs(position-reference) > epsilon {
+			reference, stable = position, 1
+			continue
+		}
+		stable++
+	}
+	return nil
+}
+
+/*
+Waits until the motor is neither moving nor jogging, then
+dwells for the configured settle delay
+*/
+func (k *KDC101) waitForStop(ctx context.Context, channel uint8, timeout time.Duration) error {
+	err := k.waitFor(ctx, channel, func(bits DCStatusBits) bool {
+		return !bits.InMotionCW && !bits.InMotionCCW && !bits.JoggingCW && !bits.JoggingCCW
+	}, MotionPollInterval, timeout)
+	if errors.Is(err, ErrMoveStopped) {
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(k.SettleDelay):
+		return nil
+	}
+}
+
+/*
+Homes the specified channel and waits until the homing
+sequence completes. ErrHomingFailed is returned if the
+controller gives up before the stage is homed (e.g. the home
+limit switch is never found), which is reported either by
+its move stopped message or by homing ending unhomed.
+A stage already homed must first show homing, or lose its
+homed bit, so a status read before homing began is not
+taken for its end; if neither shows up within
+HomeStartGrace, homing is taken as completed at once.
+Cancelling the context stops the motor
+*/
+func (k *KDC101) HomeAndWait(ctx context.Context, channel uint8, timeout time.Duration) error {
+	if err := k.StartHomeMove(channel); err != nil {
+		return err
+	}
+	var last DCStatusBits
+	homing, cleared := false, false
+	grace := time.Now().Add(HomeStartGrace)
+	err := k.waitFor(ctx, channel, func(bits DCStatusBits) bool {
+		last = bits
+		homing = homing || bits.IsHoming
+		cleared = cleared || !bits.IsHomed
+		switch {
+		case bits.IsHoming:
+			return false
+		case homing:
+			return true // Homing ended, homed or not
+		case cleared:
+			return bits.IsHomed // Homed again, the homing bit was missed
+		}
+		return time.Now().After(grace)
+	}, MotionPollInterval, timeout)
+	if errors.Is(err, ErrMoveStopped) {
+		return ErrHomingFailed
+	}
+	if err != nil {
+		return err
+	}
+	if !last.IsHomed {
+		return ErrHomingFailed
+	}
+	return nil
+}
+
+/*
+Estimates how long a move between two positions takes with
+the trapezoidal velocity profile configured on the channel:
+the motor accelerates up to the maximum velocity, cruises
+and decelerates, or never reaches it on short moves. The
+estimate ignores settling and communication delays
+*/
+func (k *KDC101) EstimateMoveTime(channel uint8, fromPos, toPos float64) (time.Duration, error) {
+	profile, err := k.GetTrapezoidalVelocity(channel)
+	if err != nil {
+		return 0, err
+	}
+	return estimateMoveTime(profile, math.Abs(toPos-fromPos))
+}
+
+func estimateMoveTime(profile VelocityProfile, distance float64) (time.Duration, error) {
+	velocity, acceleration := profile.MaxVelocity, profile.Acceleration
+	if velocity <= 0 || acceleration <= 0 {
+		return 0, ErrInvalidVelocityProfile
+	}
+	var seconds float64
+	if ramp := velocity * velocity / acceleration; distance >= ramp {
+		seconds = distance/velocity + velocity/acceleration
+	} else {
+		seconds = 2 * math.Sqrt(distance/acceleration)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+/*
+Returns the timeout given by the caller, or derives one from
+the estimated travel time when it is zero: one and a half
+times the estimate plus MoveTimeoutMargin
+*/
+func (k *KDC101) moveTimeout(channel uint8, distance float64, timeout time.Duration) (time.Duration, error) {
+	if timeout != 0 {
+		return timeout, nil
+	}
+	profile, err := k.GetTrapezoidalVelocity(channel)
+	if err != nil {
+		return 0, err
+	}
+	estimate, err := estimateMoveTime(profile, math.Abs(distance))
+	if err != nil {
+		return 0, err
+	}
+	return estimate + estimate/2 + MoveTimeoutMargin, nil
+}
+
+/*
+Returns the timeout for an absolute move from the current
+position, deriving it from the travel time when it is zero
+*/
+func (k *KDC101) absoluteMoveTimeout(channel uint8, position float64, timeout time.Duration) (time.Duration, error) {
+	if timeout != 0 {
+		return timeout, nil
+	}
+	current, err := k.getRawPosition(channel)
+	if err != nil {
+		return 0, err
+	}
+	return k.moveTimeout(channel, position-current, timeout)
+}
+
+/*
+Moves to the absolute position and waits until the motor
+has stopped. Cancelling the context stops the motor. A zero
+timeout is derived from the estimated travel time
+*/
+func (k *KDC101) MoveAbsoluteAndWait(ctx context.Context, channel uint8, position float64, timeout time.Duration) error {
+	timeout, err := k.absoluteMoveTimeout(channel, position, timeout)
+	if err != nil {
+		return err
+	}
+	if err := k.MoveAbsolutePosition(channel, position); err != nil {
+		return err
+	}
+	return k.waitForStop(ctx, channel, timeout)
+}
+
+/*
+Moves by the relative distance and waits until the motor
+has stopped. Cancelling the context stops the motor. A zero
+timeout is derived from the estimated travel time
+*/
+func (k *KDC101) MoveRelativeAndWait(ctx context.Context, channel uint8, distance float64, timeout time.Duration) error {
+	timeout, err := k.moveTimeout(channel, distance, timeout)
+	if err != nil {
+		return err
+	}
+	if err := k.MoveRelativeDistance(channel, distance); err != nil {
+		return err
+	}
+	return k.waitForStop(ctx, channel, timeout)
+}
+
+/*
+Moves to the absolute position and blocks until the
+controller reports the end of the move with its move
+completed message, instead of polling the status. End of
+move messages are resumed for the move, and suspended again
+afterwards if SuspendEndOfMove is set. ErrMoveStopped is
+returned if the move is stopped before completing. A zero
+timeout is derived from the estimated travel time
+*/
+func (k *KDC101) MoveAbsoluteWaitCompleted(channel uint8, position float64, timeout time.Duration) error {
+	timeout, err := k.absoluteMoveTimeout(channel, position, timeout)
+	if err != nil {
+		return err
+	}
+	return k.moveWaitCompleted(channel, timeout, func() error {
+		return k.MoveAbsolutePosition(channel, position)
+	})
+}
+
+/*
+Moves by the relative distance and blocks until the move
+completed message is received, like MoveAbsoluteWaitCompleted
+*/
+func (k *KDC101) MoveRelativeWaitCompleted(channel uint8, distance float64, timeout time.Duration) error {
+	timeout, err := k.moveTimeout(channel, distance, timeout)
+	if err != nil {
+		return err
+	}
+	return k.moveWaitCompleted(channel, timeout, func() error {
+		return k.MoveRelativeDistance(channel, distance)
+	})
+}
+
+/*
+Resumes the end of move messages, issues the move and reads
+frames until the move completed or move stopped message for
+the channel arrives
+*/
+func (k *KDC101) moveWaitCompleted(channel uint8, timeout time.Duration, move func() error) error {
+	if _, err := k.channelBitmask(channel); err != nil {
+		return err
+	}
+	if err := k.ResumeEndOfMoveMessages(); err != nil {
+		return err
+	}
+	if k.SuspendEndOfMove {
+		defer k.SuspendEndOfMoveMessages()
+	}
+	if err := move(); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		var id uint16
+		var ident byte
+		msg, err := k.ReadData()
+		var header *HeaderOnlyFrameError
+		if errors.As(err, &header) {
+			id, ident = header.Header.ID, header.Header.Parameter1
+		} else if errors.Is(err, ErrTimeout) || errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, ErrInvalidResponseLength) {
+			continue // Nothing, or a cut frame, received within the read timeout
+		} else if err != nil {
+			return err
+		} else if len(msg.Data) > 0 {
+			id, ident = msg.ID, msg.Data[0]
+		}
+		if ident != byte(1<<(channel-1)) {
+			continue
+		}
+		switch id {
+		case msgMotMoveCompleted:
+			return nil
+		case msgMotMoveStopped:
+			return ErrMoveStopped
+		}
+	}
+	return ErrMoveTimeout
+}
+
+/*
+Stops the motor and waits until it has come to rest, so the
+next command does not race the deceleration. Returns at the
+first poll if the motor was already stopped
+*/
+func (k *KDC101) StopAndWait(channel uint8, mode StopMode, timeout time.Duration) error {
+	if err := k.Stop(channel, mode); err != nil {
+		return err
+	}
+	return k.waitForStop(context.Background(), channel, timeout)
+}
+
+/*
+Moves to each position in order, waiting for every move to
+complete and then for the settle time before the next one.
+The sequence is aborted on the first error or if the status
+reports a fault, returning a SequenceError with the index of
+the failed position. The timeout applies to each move
+*/
+func (k *KDC101) MoveSequence(channel uint8, positions []float64, settle time.Duration, timeout time.Duration) error {
+	for i, position := range positions {
+		if err := k.MoveAbsoluteAndWait(context.Background(), channel, position, timeout); err != nil {
+			return &SequenceError{Index: i, Err: err}
+		}
+		if err := k.checkFaults(channel); err != nil {
+			return &SequenceError{Index: i, Err: err}
+		}
+		if i < len(positions)-1 {
+			time.Sleep(settle)
+		}
+	}
+	return nil
+}
+
+/*
+Brings the stage to a safe state for shutdown: homes it if
+needed, moves to the park position, waits for it to settle
+and disables the channel. The timeout covers the whole
+sequence, and cancelling the context stops the motor
+*/
+func (k *KDC101) Park(ctx context.Context, channel uint8, parkPosition float64, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	homed, err := k.IsHomed(channel)
+	if err != nil {
+		return err
+	}
+	if !homed {
+		if err := k.HomeAndWait(ctx, channel, time.Until(deadline)); err != nil {
+			return fmt.Errorf("homing: %w", err)
+		}
+	}
+	if err := k.MoveAbsoluteAndWait(ctx, channel, parkPosition, time.Until(deadline)); err != nil {
+		return fmt.Errorf("moving to park position: %w", err)
+	}
+	err = k.waitFor(ctx, channel, func(bits DCStatusBits) bool {
+		return bits.IsSettled
+	}, MotionPollInterval, time.Until(deadline))
+	if err != nil {
+		return fmt.Errorf("settling: %w", err)
+	}
+	return k.Enable(channel, false)
+}
+
+/*
+Measures the distance covered by one single step jog in the
+specified direction by reading the position before and
+after it. Comparing it with the configured step size detects
+a wrong stage type or mechanical slip
+*/
+func (k *KDC101) CalibrateJogStep(channel uint8, direction Direction) (float64, error) {
+	params, err := k.GetJogParameters(channel)
+	if err != nil {
+		return 0, err
+	}
+	if params.Mode != JogModeSingleStep {
+		return 0, fmt.Errorf("jog calibration requires single step jog mode")
+	}
+	start, err := k.getRawPosition(channel)
+	if err != nil {
+		return 0, err
+	}
+	if err := k.StartJogMove(channel, direction); err != nil {
+		return 0, err
+	}
+	if err := k.waitForStop(context.Background(), channel, DefaultMoveTimeout); err != nil {
+		return 0, err
+	}
+	end, err := k.getRawPosition(channel)
+	if err != nil {
+		return 0, err
+	}
+	return end - start, nil
+}
@@ -0,0 +1,355 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/thorlabs-kdc101/protocol"
+)
+
+func TestCancelMoveSendsStop(t *testing.T) {
+	controller, transport := newFakeController(func(frame []byte) []byte {
+		if frame[0] != 0x90 || frame[1] != 0x04 {
+			return nil
+		}
+		return statusFrame(0x0491, 0, 0x10) // Always moving clockwise
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	err := controller.MoveAbsoluteAndWait(ctx, 1, 10, time.Minute)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, expected context.DeadlineExceeded", err)
+	}
+	last := transport.written[len(transport.written)-1]
+	if last[0] != 0x65 || last[1] != 0x04 || last[3] != 0x02 {
+		t.Errorf("last frame written: got % X, expected a profiled stop", last)
+	}
+}
+
+func TestMoveSequenceFault(t *testing.T) {
+	reads := 0
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		if frame[0] != 0x90 || frame[1] != 0x04 {
+			return nil
+		}
+		reads++
+		var bits uint32
+		if reads >= 3 {
+			bits = 0x01000000 // Overload from the second move on
+		}
+		return statusFrame(0x0491, 0, bits)
+	})
+
+	err := controller.MoveSequence(1, []float64{1, 2, 3}, 0, time.Second)
+	var sequence *protocol.SequenceError
+	if !errors.As(err, &sequence) || sequence.Index != 1 {
+		t.Fatalf("got %v, expected a SequenceError for index 1", err)
+	}
+	if !errors.Is(err, protocol.ErrMotorFault) {
+		t.Errorf("got %v, expected ErrMotorFault", err)
+	}
+}
+
+func TestMoveWaitCompleted(t *testing.T) {
+	controller, transport := newFakeController(func(frame []byte) []byte {
+		if frame[0] != 0x53 || len(frame) != 12 {
+			return nil
+		}
+		return append(statusFrame(0x0491, 0, 0), statusFrame(0x0464, 0, 0)...)
+	})
+
+	if err := controller.MoveAbsoluteWaitCompleted(1, 1.0, time.Second); err != nil {
+		t.Fatalf("MoveAbsoluteWaitCompleted: %v", err)
+	}
+	if resume := transport.written[0]; resume[0] != 0x6C || resume[1] != 0x04 {
+		t.Errorf("got % X written first, expected the resume end of move messages request", resume)
+	}
+}
+
+/*
+Transport failing its first reads with os.ErrDeadlineExceeded,
+like a TCP connection quiet past its read deadline
+*/
+type deadlineTransport struct {
+	*fakeTransport
+	quiet int
+}
+
+func (d *deadlineTransport) Read(size uint) ([]byte, error) {
+	if d.quiet > 0 {
+		d.quiet--
+		return nil, os.ErrDeadlineExceeded
+	}
+	return d.fakeTransport.Read(size)
+}
+
+func TestMoveWaitCompletedSkipsQuietReads(t *testing.T) {
+	transport := &deadlineTransport{quiet: 3, fakeTransport: &fakeTransport{respond: func(frame []byte) []byte {
+		if frame[0] != 0x53 {
+			return nil
+		}
+		return statusFrame(0x0464, 0, 0)
+	}}}
+	controller := &protocol.KDC101{Communication: transport, StageType: "MTS25-Z8"}
+
+	if err := controller.MoveAbsoluteWaitCompleted(1, 1.0, time.Second); err != nil {
+		t.Errorf("got %v, expected the move completed after the quiet reads", err)
+	}
+}
+
+func TestWaitForStable(t *testing.T) {
+	positions := []uint32{0, 500, 1000, 1001, 1000, 1002}
+	reads := 0
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		counts := positions[min(reads, len(positions)-1)]
+		reads++
+		response := []byte{0x12, 0x04, 0x06, 0x00, 0x81, 0x50, 0x01, 0x00}
+		return append(response, byte(counts), byte(counts>>8), 0x00, 0x00)
+	})
+	epsilon := controller.CountsToPosition(3)
+
+	if err := controller.WaitForStable(1, epsilon, 4, time.Millisecond, time.Second); err != nil {
+		t.Fatalf("WaitForStable: %v", err)
+	}
+	if reads != 6 {
+		t.Errorf("got %d position reads, expected 6", reads)
+	}
+	positions = []uint32{0, 100}
+	reads = 0
+	if err := controller.WaitForStable(1, epsilon, 1000, time.Millisecond, 20*time.Millisecond); !errors.Is(err, protocol.ErrTimeout) {
+		t.Errorf("got %v, expected ErrNotStable", err)
+	}
+}
+
+func TestReadMoveStopped(t *testing.T) {
+	controller, transport := newFakeController(nil)
+	stopped := statusFrame(0x0466, 10000, 0x01)
+	transport.pending = append(append([]byte{}, stopped...), stopped...)
+
+	status, err := controller.ReadMoveStopped()
+	if err != nil {
+		t.Fatalf("ReadMoveStopped: %v", err)
+	}
+	if status.Position != 10000 || !controller.ParseDCStatusBits(status.StatusBits).CWHardLimit {
+		t.Errorf("got %+v, expected position 10000 on the forward limit", status)
+	}
+	if _, err := controller.ReadMoveCompleted(); !errors.Is(err, protocol.ErrUnexpectedMessageID) {
+		t.Errorf("ReadMoveCompleted: got %v, expected ErrUnexpectedMessageID", err)
+	}
+}
+
+/*
+Emulates a stage moving forward by step counts at every
+status poll until it reaches the limit, where the forward
+limit switch stops it
+*/
+func forwardStage(position *int32, step, limit int32) func([]byte) []byte {
+	return func(frame []byte) []byte {
+		switch frame[0] {
+		case 0x11: // Position counter
+			response := []byte{0x12, 0x04, 0x06, 0x00, 0x81, 0x50, 0x01, 0x00}
+			return binary.LittleEndian.AppendUint32(response, uint32(*position))
+		case 0x90:
+			if *position >= limit {
+				return statusFrame(0x0491, *position, 0x01) // Forward limit switch
+			}
+			*position = min(*position+step, limit)
+			return statusFrame(0x0491, *position, 0x10)
+		}
+		return nil
+	}
+}
+
+func TestMoveContinuousAndMonitorWrapsAngles(t *testing.T) {
+	controller, transport := newFakeController(nil)
+	controller.StageType, controller.AngleWrap = "PRM1-Z8", protocol.Wrap360
+	counts := controller.PositionToCounts(370)
+	transport.respond = func(frame []byte) []byte {
+		switch frame[0] {
+		case 0x11: // Position counter
+			response := []byte{0x12, 0x04, 0x06, 0x00, 0x81, 0x50, 0x01, 0x00}
+			return binary.LittleEndian.AppendUint32(response, uint32(counts))
+		case 0x90:
+			return statusFrame(0x0491, counts, 0x10)
+		}
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	positions, err := controller.MoveContinuousAndMonitor(ctx, 1, protocol.Forward, time.Millisecond)
+	if err != nil {
+		t.Fatalf("MoveContinuousAndMonitor: %v", err)
+	}
+	expected, err := controller.GetPosition(1)
+	if err != nil {
+		t.Fatalf("GetPosition: %v", err)
+	}
+	if position := <-positions; math.Abs(position-expected) > 1e-9 || math.Abs(position-10) > 1e-3 {
+		t.Errorf("got %v, expected 10° as reported by GetPosition (%v)", position, expected)
+	}
+}
+
+func TestMoveVelocityUntil(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	controller, transport := newFakeController(nil)
+	controller.StageType, controller.AngleWrap = "PRM1-Z8", protocol.Wrap360
+	position := controller.PositionToCounts(350)
+	transport.respond = forwardStage(&position, controller.PositionToCounts(5), math.MaxInt32)
+	if err := controller.MoveVelocityUntil(ctx, 1, protocol.Forward, 370); err != nil {
+		t.Errorf("past a full turn: got %v, expected the stop position reached", err)
+	}
+
+	controller.StageType, position = "MTS25-Z8", 0
+	transport.respond = forwardStage(&position, controller.PositionToCounts(0.5), controller.PositionToCounts(2))
+	err := controller.MoveVelocityUntil(ctx, 1, protocol.Forward, 10)
+	if !errors.Is(err, protocol.ErrStoppedBeforePosition) {
+		t.Fatalf("on the limit switch: got %v, expected ErrStoppedBeforePosition", err)
+	}
+	if last := transport.written[len(transport.written)-1]; last[0] != 0x65 || last[1] != 0x04 {
+		t.Errorf("last frame written: got % X, expected a stop", last)
+	}
+}
+
+func TestMoveForDurationStops(t *testing.T) {
+	controller, transport := newFakeController(func(frame []byte) []byte {
+		if frame[0] != 0x90 || frame[1] != 0x04 {
+			return nil
+		}
+		return statusFrame(0x0491, 0, 0) // Stopped
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := controller.MoveForDuration(ctx, 1, protocol.Forward, time.Minute); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, expected context.Canceled", err)
+	}
+	move, stop := transport.written[0], transport.written[1]
+	if move[0] != 0x57 || move[1] != 0x04 {
+		t.Errorf("first frame written: got % X, expected a velocity move", move)
+	}
+	if stop[0] != 0x65 || stop[1] != 0x04 || stop[3] != 0x02 {
+		t.Errorf("second frame written: got % X, expected a profiled stop", stop)
+	}
+}
+
+func TestHomeAndWaitOnHomedStage(t *testing.T) {
+	polls := 0
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		if frame[0] != 0x90 {
+			return nil
+		}
+		polls++
+		switch {
+		case polls <= 2:
+			return statusFrame(0x0491, 0, 0x400) // Homed before, homing not shown yet
+		case polls <= 4:
+			return statusFrame(0x0491, 0, 0x200) // Homing
+		}
+		return statusFrame(0x0491, 0, 0x400)
+	})
+
+	if err := controller.HomeAndWait(context.Background(), 1, time.Second); err != nil {
+		t.Fatalf("HomeAndWait: %v", err)
+	}
+	if polls != 5 {
+		t.Errorf("returned after %d polls, expected 5 once homing ended", polls)
+	}
+	polls = -100 // Homing never shown: completed at once
+	if err := controller.HomeAndWait(context.Background(), 1, time.Second); err != nil {
+		t.Errorf("without homing shown: got %v, expected success after HomeStartGrace", err)
+	}
+}
+
+func TestEstimateMoveTime(t *testing.T) {
+	var velocity, acceleration uint32
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		response := []byte{0x15, 0x04, 0x0E, 0x00, 0x81, 0x50, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00}
+		response = binary.LittleEndian.AppendUint32(response, acceleration)
+		return binary.LittleEndian.AppendUint32(response, velocity)
+	})
+
+	if _, err := controller.EstimateMoveTime(1, 0, 10); !errors.Is(err, protocol.ErrInvalidVelocityProfile) {
+		t.Errorf("got %v, expected ErrInvalidVelocityProfile", err)
+	}
+	velocity, acceleration = controller.VelocityToCounts(2), controller.AccelerationToCounts(1)
+	cases := map[float64]time.Duration{
+		10: 7 * time.Second, // 2 s ramping, 5 s cruising
+		1:  2 * time.Second, // Never reaches the maximum velocity
+	}
+	for distance, expected := range cases {
+		estimate, err := controller.EstimateMoveTime(1, 5, 5-distance)
+		if err != nil {
+			t.Fatalf("EstimateMoveTime: %v", err)
+		}
+		if math.Abs(float64(estimate-expected)) > 0.01*float64(expected) {
+			t.Errorf("%v mm: got %v, expected about %v", distance, estimate, expected)
+		}
+	}
+}
+
+func TestMoveCompletedAnswersStatusPoll(t *testing.T) {
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		if frame[0] != 0x90 {
+			return nil
+		}
+		// Move completed sent by the controller in place of the update
+		return statusFrame(0x0464, 10000, 0)
+	})
+
+	if _, err := controller.GetDCStatusUpdate(1); err != nil {
+		t.Fatalf("GetDCStatusUpdate: %v", err)
+	}
+	if err := controller.MoveAbsoluteAndWait(context.Background(), 1, 1.0, time.Second); err != nil {
+		t.Errorf("MoveAbsoluteAndWait: %v", err)
+	}
+}
+
+func TestMoveWithProfileRestoresAfterTheMove(t *testing.T) {
+	store := storeParameters(0x0413)
+	polls := 0
+	controller, transport := newFakeController(func(frame []byte) []byte {
+		switch frame[0] {
+		case 0x11: // Position counter
+			return []byte{0x12, 0x04, 0x06, 0x00, 0x81, 0x50, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00}
+		case 0x90:
+			polls++
+			var bits uint32
+			if polls == 1 {
+				bits = 0x10 // Still moving at the first poll
+			}
+			return statusFrame(0x0491, 0, bits)
+		}
+		return store(frame)
+	})
+	if err := controller.SetTrapezoidalVelocity(1, protocol.VelocityProfile{MaxVelocity: 1, Acceleration: 1}); err != nil {
+		t.Fatalf("SetTrapezoidalVelocity: %v", err)
+	}
+
+	profile := protocol.VelocityProfile{MaxVelocity: 2, Acceleration: 2}
+	if err := controller.MoveAbsoluteWithProfile(1, 1.0, profile, true); err != nil {
+		t.Fatalf("MoveAbsoluteWithProfile: %v", err)
+	}
+	last := transport.written[len(transport.written)-1]
+	if last[0] != 0x13 || polls != 2 {
+		t.Errorf("got % X last after %d polls, expected the profile restored once the motor stopped", last, polls)
+	}
+	restored, err := controller.GetTrapezoidalVelocity(1)
+	if err != nil || math.Abs(restored.MaxVelocity-1) > 1e-3 {
+		t.Errorf("got %+v, %v, expected the previous profile back", restored, err)
+	}
+}
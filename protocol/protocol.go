@@ -1,16 +1,23 @@
 /*
 Author: Leonardo Rossi Leao
 Created at: September 26th, 2025
-Last update: September 26th, 2025
+Last update: October 16th, 2026
 */
 
 package protocol
 
 import (
+	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
+	"sync"
 	"time"
 
 	"github.com/devicehub-go/unicomm"
+	"github.com/devicehub-go/unicomm/protocol/unicommserial"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
 )
 
 type Endpoint byte
@@ -31,10 +38,102 @@ type DataMessage struct {
 	Data        []byte
 }
 
+type Logger interface {
+	Logf(format string, args ...any)
+}
+
 type KDC101 struct {
 	Communication unicomm.Unicomm
 	StageType string // e.g., "MTS25-Z8", "MTS50-Z8", etc.
 	MotorType string // e.g., "Brushed", "Brushless"
+
+	LastResponse []byte       // Raw bytes of the most recent frame read
+	DebugHook    func([]byte) // Optional, called with every raw frame read
+	Logger       Logger       // Optional, logs every frame sent and received
+
+	// By default WriteData sets the 0x80 flag on the destination
+	// byte, which marks the frame as a data packet per APT. When
+	// set, the destination is sent exactly as given instead
+	RawDestination bool
+
+	// Byte order of the multi-byte fields of data messages,
+	// little-endian per APT when nil. Some relabeled clones
+	// use big-endian; frame headers are not affected
+	ByteOrder binary.ByteOrder
+
+	// Normalization of the positions read from rotary stages
+	AngleWrap AngleWrap
+
+	// Rejects absolute targets outside the stage travel range
+	ValidateTravel bool
+
+	// Clamps absolute targets to [0, MaxTravelCounts] instead,
+	// taking precedence over ValidateTravel. Rotation mounts
+	// have no end of travel and are never clamped
+	ClampMoves bool
+
+	// Dwell applied by the blocking move helpers once the motor
+	// has stopped, letting mechanical ringing damp out
+	SettleDelay time.Duration
+
+	// When set, frames are written in chunks of this many bytes
+	// separated by WriteChunkDelay, for USB to serial adapters
+	// dropping bytes of frames written at once
+	WriteChunkSize  int
+	WriteChunkDelay time.Duration
+
+	// Number of times RequestHeaderOnly and RequestData repeat
+	// the whole exchange when the response is lost or garbled.
+	// Writes without a response are never repeated
+	RequestRetries int
+
+	// Applied by Connect once the connection is open: enables
+	// channel 1 and suspends the end of move messages
+	AutoEnable       bool
+	SuspendEndOfMove bool
+
+	// Applied by Connect on serial ports: asserts the DTR and
+	// RTS lines, which the controller uses for RTS/CTS flow
+	// control and stays silent without on some adapters
+	AssertRTS bool
+
+	// Number of motor channels accepted by the channel checks,
+	// 1 when zero and at most 8. The KDC101 has one channel;
+	// raising it lets the protocol layer address multi-channel
+	// APT controllers, although the rest assumes a single one
+	MaxChannels uint8
+
+	ioMutex        sync.Mutex // Serializes every frame exchange
+	mutex          sync.Mutex // Keeps multi-command sequences atomic
+	relativeStep   *float64   // Last relative move distance set or read
+	absoluteTarget *int32     // Target of the last absolute move sent with its position
+
+	cacheMutex   sync.Mutex // Protects the cached statuses
+	cachedStatus map[uint8]cachedStatus
+
+	historyMutex sync.Mutex // Protects the position history
+	history      []PositionSample
+	historyNext  int
+
+	watchMutex sync.Mutex // Protects the position watchers
+	watchers   []positionWatcher
+	watching   bool
+
+	presetMutex sync.Mutex // Protects the presets
+	presets     map[string]Preset
+
+	subscribeMutex sync.Mutex // Protects the status subscribers
+	subscribers    map[chan DCStatusUpdateSI]struct{}
+	polling        bool
+
+	lifeMutex sync.Mutex     // Protects done
+	done      chan struct{}  // Closed by Close to stop the background goroutines
+	workers   sync.WaitGroup // Background goroutines running
+
+	metrics metrics // Frame counters, see Metrics
+
+	recordMutex sync.Mutex // Protects the recording writer
+	recorder    io.Writer
 }
 
 const (
@@ -43,11 +142,52 @@ const (
 	GenericUnit Endpoint = 0x50
 )
 
-var ErrChannelNotSupported = fmt.Errorf("KDC101 just supports channel 1")
+var ErrChannelNotSupported = fmt.Errorf("channel not supported")
 var ErrInvalidResponseLength = fmt.Errorf("invalid response length")
+var ErrTimeoutNotSupported = fmt.Errorf("transport does not support changing timeouts")
+var ErrHeaderOnlyFrame = errors.New("header only frame received")
+var ErrUnexpectedMessageID = errors.New("unexpected message ID")
+var ErrUnsupportedByFirmware = errors.New("not supported by the controller firmware")
+
+// Matched by every timeout of this package, e.g. a response
+// not received in time or ErrMoveTimeout
+var ErrTimeout = errors.New("timeout")
+
+var errResponseTimeout = fmt.Errorf("%w: %w", ErrTimeout, ErrInvalidResponseLength)
+
 var InvalidHeader HeaderMessage = HeaderMessage{}
 var InvalidData   DataMessage = DataMessage{}
 
+/*
+Returns the channel ident bitmask of APT messages for the
+specified channel (0x01 for channel 1, 0x02 for channel 2
+and so on), or ErrChannelNotSupported for a channel the
+controller does not have
+*/
+func (k *KDC101) channelBitmask(channel uint8) (byte, error) {
+	if channel < 1 || channel > min(max(k.MaxChannels, 1), 8) {
+		return 0, fmt.Errorf("%w: %d", ErrChannelNotSupported, channel)
+	}
+	return byte(1 << (channel - 1)), nil
+}
+
+/*
+Returned by ReadData when the frame read is a header only
+message. It carries the parsed header so the caller can
+handle it and read again
+*/
+type HeaderOnlyFrameError struct {
+	Header HeaderMessage
+}
+
+func (e *HeaderOnlyFrameError) Error() string {
+	return fmt.Sprintf("%s: 0x%04X", ErrHeaderOnlyFrame, e.Header.ID)
+}
+
+func (e *HeaderOnlyFrameError) Is(target error) bool {
+	return target == ErrHeaderOnlyFrame
+}
+
 /*
 Establishes a connection with the device
 */
@@ -55,9 +195,50 @@ func (k *KDC101) Connect() error {
 	if err := k.Communication.Connect(); err != nil {
 		return err
 	}
+	if k.AssertRTS {
+		if err := k.assertControlLines(); err != nil {
+			k.Communication.Disconnect()
+			return fmt.Errorf("asserting control lines: %w", err)
+		}
+	}
+	if k.SuspendEndOfMove {
+		if err := k.SuspendEndOfMoveMessages(); err != nil {
+			k.Communication.Disconnect()
+			return fmt.Errorf("suspending end of move messages: %w", err)
+		}
+	}
+	if k.AutoEnable {
+		if err := k.Enable(1, true); err != nil {
+			k.Communication.Disconnect()
+			return fmt.Errorf("enabling channel: %w", err)
+		}
+	}
 	return nil
 }
 
+/*
+Connects like Connect but gives up when the context is done,
+returning its error, so a missing device cannot hang the
+caller. A connection completing after that is closed again
+*/
+func (k *KDC101) ConnectContext(ctx context.Context) error {
+	result := make(chan error, 1)
+	go func() {
+		result <- k.Connect()
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		go func() {
+			if err := <-result; err == nil {
+				k.Communication.Disconnect()
+			}
+		}()
+		return ctx.Err()
+	}
+}
+
 /*
 Closes the connection with the device
 */
@@ -65,6 +246,24 @@ func (k *KDC101) Disconnect() error {
 	return k.Communication.Disconnect()
 }
 
+/*
+Asks the controller to disconnect from the USB bus and closes
+the local connection, so Connect must be called again once
+the device is back. The APT protocol has no reset message for
+the KDC101, so this does not reboot the controller
+*/
+func (k *KDC101) DisconnectFromBus() error {
+	err := k.WriteHeaderOnly(HeaderMessage{
+		ID:          msgHwDisconnect,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+	if err != nil {
+		return err
+	}
+	return k.Communication.Disconnect()
+}
+
 /*
 Returns true if device is connected
 */
@@ -72,102 +271,458 @@ func (k *KDC101) IsConnected() bool {
 	return k.Communication.IsConnected()
 }
 
+/*
+Checks that the controller answers by requesting the channel
+enable state, which neither moves the motor nor changes any
+setting, so it is safe to call at high frequency
+*/
+func (k *KDC101) Ping() error {
+	response, err := k.RequestHeaderOnly(HeaderMessage{
+		ID:          msgModReqChanEnableState,
+		Parameter1:  0x01,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+	if err != nil {
+		return err
+	}
+	if response.ID != msgModGetChanEnableState {
+		return fmt.Errorf("%w: ping answered with 0x%04X", ErrUnexpectedMessageID, response.ID)
+	}
+	return nil
+}
+
+/*
+Changes the read timeout of the underlying transport. For
+serial ports the new timeout is applied to the open port
+immediately, so it can be changed between commands (e.g. a
+long timeout while homing, a short one for status polls).
+An exchange in progress completes with the previous timeout
+*/
+func (k *KDC101) SetReadTimeout(timeout time.Duration) error {
+	k.ioMutex.Lock()
+	defer k.ioMutex.Unlock()
+
+	switch transport := k.Communication.(type) {
+	case *unicommserial.UnicommSerial:
+		transport.Options.ReadTimeout = timeout
+		if transport.Connection != nil {
+			return transport.Connection.SetReadTimeout(timeout)
+		}
+		return nil
+	case *unicommtcp.UnicommTCP:
+		transport.Options.ReadTimeout = timeout
+		return nil
+	}
+	return ErrTimeoutNotSupported
+}
+
+/*
+Asserts the DTR and RTS lines of a serial port, as the
+Thorlabs software does when it opens the FTDI bridge of the
+controller. Transports without control lines are left alone
+*/
+func (k *KDC101) assertControlLines() error {
+	transport, ok := k.Communication.(*unicommserial.UnicommSerial)
+	if !ok || transport.Connection == nil {
+		return nil
+	}
+	if err := transport.Connection.SetDTR(true); err != nil {
+		return err
+	}
+	return transport.Connection.SetRTS(true)
+}
+
+/*
+Changes the write timeout stored in the options of the
+underlying transport. Neither transport bounds writes with
+it: serial ports write synchronously, and the unicomm TCP
+transport applies it as a read deadline before writing,
+which the next read replaces with its own
+*/
+func (k *KDC101) SetWriteTimeout(timeout time.Duration) error {
+	k.ioMutex.Lock()
+	defer k.ioMutex.Unlock()
+
+	switch transport := k.Communication.(type) {
+	case *unicommserial.UnicommSerial:
+		transport.Options.WriteTimeout = timeout
+		return nil
+	case *unicommtcp.UnicommTCP:
+		transport.Options.WriteTimeout = timeout
+		return nil
+	}
+	return ErrTimeoutNotSupported
+}
+
 /*
 Writes a header only message
 */
 func (k *KDC101) WriteHeaderOnly(msg HeaderMessage) error {
-	bytes := []byte{
-		byte(msg.ID & 0x00FF),
-		byte(msg.ID >> 8),
-		msg.Parameter1,
-		msg.Parameter2,
-		byte(msg.Destination),
-		byte(msg.Source),
-	}
-	return k.Communication.Write(bytes)
+	k.ioMutex.Lock()
+	defer k.ioMutex.Unlock()
+	return k.writeHeaderOnly(msg)
+}
+
+func (k *KDC101) writeHeaderOnly(msg HeaderMessage) error {
+	k.logf("tx header 0x%04X", msg.ID)
+	return k.write(EncodeHeaderMessage(msg))
 }
 
 /*
-Writes a data message
+Writes a data message. The APT protocol marks data packets
+by setting the 0x80 flag on the destination byte, which is
+done here unless RawDestination is set
 */
 func (k *KDC101) WriteData(msg DataMessage) error {
-	bytes := []byte{
-		byte(msg.ID & 0x00FF),
-		byte(msg.ID >> 8),
-		byte(msg.DataLength & 0x00FF),
-		byte(msg.DataLength >> 8),
-		byte(msg.Destination) | 0x80,
-		byte(msg.Source),
+	k.ioMutex.Lock()
+	defer k.ioMutex.Unlock()
+	return k.writeData(msg)
+}
+
+func (k *KDC101) writeData(msg DataMessage) error {
+	frame := EncodeDataMessage(msg)
+	if k.RawDestination {
+		frame[4] = byte(msg.Destination)
 	}
-	bytes = append(bytes, msg.Data...)
-	return k.Communication.Write(bytes)
+	k.logf("tx data 0x%04X (%d bytes)", msg.ID, msg.DataLength)
+	return k.write(frame)
+}
+
+/*
+Writes a whole frame, split in chunks of WriteChunkSize bytes
+separated by WriteChunkDelay when chunking is enabled
+*/
+func (k *KDC101) write(frame []byte) error {
+	var err error
+	if k.WriteChunkSize <= 0 {
+		err = k.Communication.Write(frame)
+	} else {
+		for start := 0; start < len(frame) && err == nil; start += k.WriteChunkSize {
+			if start > 0 {
+				time.Sleep(k.WriteChunkDelay)
+			}
+			err = k.Communication.Write(frame[start:min(start+k.WriteChunkSize, len(frame))])
+		}
+	}
+	k.countWrite(err)
+	if err == nil {
+		k.record("tx", frame)
+	}
+	return err
 }
 
 /*
 Reads a header only response
 */
 func (k *KDC101) ReadHeaderOnly() (HeaderMessage, error) {
-	response, err := k.Communication.Read(6)
+	k.ioMutex.Lock()
+	defer k.ioMutex.Unlock()
+	return k.readHeaderOnly(k.frameTimeout())
+}
+
+func (k *KDC101) readHeaderOnly(timeout time.Duration) (msg HeaderMessage, err error) {
+	defer func() { k.countRead(err) }()
+	response, err := k.readFull(6, time.Now().Add(timeout))
 	if err != nil {
 		return InvalidHeader, err
 	}
-	msg := HeaderMessage{
-		ID:          uint16(response[1])<<8 | uint16(response[0]),
-		Parameter1:  response[2],
-		Parameter2:  response[3],
-		Destination: Endpoint(response[4]),
-		Source:      Endpoint(response[5]),
+	msg, err = DecodeHeaderMessage(response)
+	if err != nil {
+		return InvalidHeader, err
+	}
+	if msg.ID == msgHwRichResponse && response[4]&0x80 != 0 {
+		data, err := k.readFull(int(msg.Parameter2)<<8|int(msg.Parameter1), time.Now().Add(timeout))
+		if err != nil {
+			return InvalidHeader, err
+		}
+		response = append(response, data...)
 	}
+	k.captureResponse(response)
+	if err := hardwareResponse(response); err != nil {
+		k.logf("rx %v", err)
+		return InvalidHeader, err
+	}
+	k.logf("rx header 0x%04X", msg.ID)
 	return msg, nil
 }
 
 /*
-Reads a message which contains header and data
+Reads a message which contains header and data. If a header
+only frame is received instead, a HeaderOnlyFrameError
+(matching ErrHeaderOnlyFrame) carrying it is returned, and
+a HardwareResponseError if the controller reports an error
 */
 func (k *KDC101) ReadData() (DataMessage, error) {
-	response, err := k.Communication.Read(6)
+	k.ioMutex.Lock()
+	defer k.ioMutex.Unlock()
+	return k.readData(k.frameTimeout())
+}
+
+func (k *KDC101) readData(timeout time.Duration) (DataMessage, error) {
+	deadline := time.Now().Add(timeout)
+	response, err := k.readFull(6, deadline)
 	if err != nil {
+		k.countRead(err)
 		return InvalidData, err
 	}
-	msg := DataMessage{
-		ID:          uint16(response[1])<<8 | uint16(response[0]),
-		DataLength:  uint16(response[3])<<8 | uint16(response[2]),
-		Destination: Endpoint(response[4]),
-		Source:      Endpoint(response[5]),
+	return k.readDataAfter(response, deadline)
+}
+
+/*
+Reads the data following a 6 byte header already read, if
+any, and decodes the whole frame like ReadData
+*/
+func (k *KDC101) readDataAfter(response []byte, deadline time.Time) (msg DataMessage, err error) {
+	defer func() { k.countRead(err) }()
+	frame := response
+	dataLength := uint16(response[3])<<8 | uint16(response[2])
+	if response[4]&0x80 != 0 && dataLength > 0 {
+		data, err := k.readFull(int(dataLength), deadline)
+		if err != nil {
+			k.captureResponse(append(response, data...))
+			return InvalidData, err
+		}
+		frame = append(frame, data...)
 	}
-	if msg.DataLength < 1 {
-		return InvalidData, fmt.Errorf("invalid data length: %d", msg.DataLength)
+	k.captureResponse(frame)
+	if err := hardwareResponse(frame); err != nil {
+		k.logf("rx %v", err)
+		return InvalidData, err
+	}
+
+	msg, err = DecodeDataMessage(frame)
+	var header *HeaderOnlyFrameError
+	if errors.As(err, &header) {
+		k.logf("rx header 0x%04X", header.Header.ID)
+		return InvalidData, err
 	}
-	data, err := k.Communication.Read(uint(msg.DataLength))
 	if err != nil {
 		return InvalidData, err
 	}
-	msg.Data = data
+	k.logf("rx data 0x%04X (%d bytes)", msg.ID, len(msg.Data))
 	return msg, nil
 }
 
+/*
+Returns the time allowed for a whole frame to arrive, which
+is the read timeout of the serial and TCP transports or
+DefaultFrameTimeout for any other one
+*/
+func (k *KDC101) frameTimeout() time.Duration {
+	switch transport := k.Communication.(type) {
+	case *unicommserial.UnicommSerial:
+		return transport.Options.ReadTimeout
+	case *unicommtcp.UnicommTCP:
+		return transport.Options.ReadTimeout
+	}
+	return DefaultFrameTimeout
+}
+
+/*
+Reads exactly size bytes. The transport may return fewer
+bytes than requested, or none when its timeout expires, so
+reads are repeated until the frame is complete or the
+deadline passes, in which case the bytes read so far are
+returned with an error matching both ErrTimeout and
+ErrInvalidResponseLength. This lets a request
+return as soon as the answer arrives instead of waiting a
+fixed delay
+*/
+func (k *KDC101) readFull(size int, deadline time.Time) ([]byte, error) {
+	buffer := make([]byte, 0, size)
+	for len(buffer) < size {
+		chunk, err := k.Communication.Read(uint(size - len(buffer)))
+		if err != nil {
+			return buffer, err
+		}
+		buffer = append(buffer, chunk...)
+		if len(buffer) == size {
+			break
+		}
+		if time.Now().After(deadline) {
+			return buffer, errResponseTimeout
+		}
+		if len(chunk) == 0 {
+			time.Sleep(time.Millisecond) // Transports without a blocking read
+		}
+	}
+	return buffer, nil
+}
+
+/*
+Returns ErrUnexpectedMessageID if the response is not the
+expected message, e.g. a stale frame left over from another
+request, so it is never parsed as the wrong data
+*/
+func expectResponse(response DataMessage, id uint16) error {
+	if response.ID != id {
+		return fmt.Errorf("%w: 0x%04X instead of 0x%04X", ErrUnexpectedMessageID, response.ID, id)
+	}
+	return nil
+}
+
+/*
+Writes a log line if a logger is set
+*/
+func (k *KDC101) logf(format string, args ...any) {
+	if k.Logger != nil {
+		k.Logger.Logf(format, args...)
+	}
+}
+
+/*
+Stores the raw bytes of the last frame read, records them
+and forwards them to the debug hook, if any
+*/
+func (k *KDC101) captureResponse(frame []byte) {
+	k.LastResponse = frame
+	k.record("rx", frame)
+	if k.DebugHook != nil {
+		k.DebugHook(frame)
+	}
+}
+
 /*
 Sends a header only message to device and waits for a 
-header only response.
+header only response. The whole exchange is serialized with
+any other one, so concurrent callers never read each
+other's responses. It is repeated up to RequestRetries times
+if the response is lost or garbled.
 */
 func (k *KDC101) RequestHeaderOnly(msg HeaderMessage) (HeaderMessage, error) {
-	err := k.WriteHeaderOnly(msg)
-	if err != nil {
-		return InvalidHeader, err
+	k.ioMutex.Lock()
+	defer k.ioMutex.Unlock()
+	return k.requestHeaderOnly(msg, k.RequestRetries, k.frameTimeout())
+}
+
+/*
+Same as RequestHeaderOnly, waiting up to the given timeout
+for the response instead of the transport read timeout
+*/
+func (k *KDC101) RequestHeaderOnlyTimeout(msg HeaderMessage, timeout time.Duration) (HeaderMessage, error) {
+	k.ioMutex.Lock()
+	defer k.ioMutex.Unlock()
+	return k.requestHeaderOnly(msg, k.RequestRetries, timeout)
+}
+
+func (k *KDC101) requestHeaderOnly(msg HeaderMessage, retries int, timeout time.Duration) (HeaderMessage, error) {
+	for attempt := 0; ; attempt++ {
+		err := k.writeHeaderOnly(msg)
+		if err != nil {
+			return InvalidHeader, err
+		}
+		response, err := k.readHeaderOnly(timeout)
+		if !retryable(err) || attempt >= retries {
+			return response, err
+		}
+		k.logf("retrying 0x%04X: %v", msg.ID, err)
+		k.flushInput()
 	}
-	time.Sleep(15 * time.Millisecond)
-	return k.ReadHeaderOnly()
 }
 
 /*
 Sends a header only message to device and waits for a
-data message response.
+data message response. The whole exchange is serialized with
+any other one, so concurrent callers never read each
+other's responses. It is repeated up to RequestRetries times
+if the response is lost or garbled.
 */
 func (k *KDC101) RequestData(msg HeaderMessage) (DataMessage, error) {
-	err := k.WriteHeaderOnly(msg)
+	k.ioMutex.Lock()
+	defer k.ioMutex.Unlock()
+	return k.requestData(msg, k.frameTimeout())
+}
+
+/*
+Same as RequestData, waiting up to the given timeout for the
+response instead of the transport read timeout, e.g. a short
+one for fast status polls. Each read of the transport still
+blocks up to its own timeout, and TCP reads fail once it
+expires, so the transport timeout bounds how far a single
+read can deviate
+*/
+func (k *KDC101) RequestDataTimeout(msg HeaderMessage, timeout time.Duration) (DataMessage, error) {
+	k.ioMutex.Lock()
+	defer k.ioMutex.Unlock()
+	return k.requestData(msg, timeout)
+}
+
+func (k *KDC101) requestData(msg HeaderMessage, timeout time.Duration) (DataMessage, error) {
+	for attempt := 0; ; attempt++ {
+		err := k.writeHeaderOnly(msg)
+		if err != nil {
+			return InvalidData, err
+		}
+		response, err := k.readData(timeout)
+		if !retryable(err) || attempt >= k.RequestRetries {
+			return response, err
+		}
+		k.logf("retrying 0x%04X: %v", msg.ID, err)
+		k.flushInput()
+	}
+}
+
+/*
+Tells whether a failed read is worth repeating the request
+for. A header only frame is a valid message from the
+controller, such as a move stopped event, and so is a
+hardware response, so both are returned to the caller
+*/
+func retryable(err error) bool {
+	return err != nil && !errors.Is(err, ErrHeaderOnlyFrame) && !errors.Is(err, ErrHardwareResponse)
+}
+
+/*
+Discards the bytes left over by a failed exchange, so the
+next attempt does not read the tail of the previous response
+*/
+func (k *KDC101) flushInput() {
+	for range 16 {
+		chunk, err := k.Communication.Read(64)
+		if err != nil || len(chunk) == 0 {
+			return
+		}
+	}
+}
+
+/*
+Sends an arbitrary header only message to the controller and
+waits for a header only response. It bypasses every
+validation done by the typed API, so it is meant for
+messages this library does not cover yet. It is never
+repeated, since the message may start a move
+*/
+func (k *KDC101) SendHeaderOnly(id uint16, param1, param2 byte) (HeaderMessage, error) {
+	k.ioMutex.Lock()
+	defer k.ioMutex.Unlock()
+	return k.requestHeaderOnly(HeaderMessage{
+		ID:          id,
+		Parameter1:  param1,
+		Parameter2:  param2,
+		Destination: GenericUnit,
+		Source:      Host,
+	}, 0, k.frameTimeout())
+}
+
+/*
+Sends an arbitrary data message to the controller and waits
+for a data response. It bypasses every validation done by
+the typed API; for messages without a reply use WriteData
+*/
+func (k *KDC101) SendData(id uint16, data []byte) (DataMessage, error) {
+	k.ioMutex.Lock()
+	defer k.ioMutex.Unlock()
+
+	err := k.writeData(DataMessage{
+		ID:          id,
+		Data:        data,
+		DataLength:  uint16(len(data)),
+		Destination: GenericUnit,
+		Source:      Host,
+	})
 	if err != nil {
 		return InvalidData, err
 	}
-	time.Sleep(50 * time.Millisecond)
-	return k.ReadData()
+	return k.readData(k.frameTimeout())
 }
@@ -0,0 +1,273 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/thorlabs-kdc101/protocol"
+	"github.com/devicehub-go/unicomm/protocol/unicommtcp"
+)
+
+/*
+In-memory transport answering every frame written with the
+frame returned by respond, queued for the following reads
+*/
+type fakeTransport struct {
+	respond func(frame []byte) []byte
+	written [][]byte
+	pending []byte
+	chunk   int // When set, reads return at most chunk bytes
+
+	mutex sync.Mutex
+}
+
+func (f *fakeTransport) Connect() error    { return nil }
+func (f *fakeTransport) Disconnect() error { return nil }
+func (f *fakeTransport) IsConnected() bool { return true }
+
+func (f *fakeTransport) Read(size uint) ([]byte, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	n := min(int(size), len(f.pending))
+	if f.chunk > 0 {
+		n = min(n, f.chunk)
+	}
+	data := append([]byte{}, f.pending[:n]...)
+	f.pending = f.pending[n:]
+	return data, nil
+}
+
+func (f *fakeTransport) ReadUntil(delimiter string) ([]byte, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (f *fakeTransport) Write(message []byte) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.written = append(f.written, append([]byte{}, message...))
+	if f.respond != nil {
+		f.pending = append(f.pending, f.respond(message)...)
+	}
+	return nil
+}
+
+/*
+Answers header only requests echoing their parameters, so
+each response can be matched with its request
+*/
+func echoHeader(frame []byte) []byte {
+	return []byte{frame[0] + 1, frame[1], frame[2], frame[3], byte(protocol.Host), byte(protocol.GenericUnit)}
+}
+
+/*
+Builds the 20 byte status frame of channel 1 carrying the
+given position and status bits, as sent in the DC status
+update (0x0491) and the end of move messages
+*/
+func statusFrame(id uint16, position int32, bits uint32) []byte {
+	frame := []byte{byte(id), byte(id >> 8), 0x0E, 0x00, 0x81, 0x50, 0x01, 0x00}
+	frame = binary.LittleEndian.AppendUint32(frame, uint32(position))
+	frame = append(frame, 0x00, 0x00, 0x00, 0x00) // Velocity and reserved
+	return binary.LittleEndian.AppendUint32(frame, bits)
+}
+
+func newFakeController(respond func([]byte) []byte) (*protocol.KDC101, *fakeTransport) {
+	transport := &fakeTransport{respond: respond}
+	controller := &protocol.KDC101{
+		Communication: transport,
+		StageType:     "MTS25-Z8",
+		MotorType:     "Brushed",
+	}
+	return controller, transport
+}
+
+/*
+Issues requests from four goroutines and returns the number
+of responses that did not match their request
+*/
+func concurrentRequests(controller *protocol.KDC101, perCaller int) int {
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	corrupted := 0
+	for caller := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perCaller {
+				response, err := controller.SendHeaderOnly(0x0211, byte(caller), byte(i))
+				if err != nil || response.Parameter1 != byte(caller) || response.Parameter2 != byte(i) {
+					mutex.Lock()
+					corrupted++
+					mutex.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return corrupted
+}
+
+func TestConcurrentRequests(t *testing.T) {
+	controller, _ := newFakeController(echoHeader)
+	if corrupted := concurrentRequests(controller, 5); corrupted != 0 {
+		t.Errorf("%d responses did not match their request", corrupted)
+	}
+}
+
+func BenchmarkConcurrentRequests(b *testing.B) {
+	controller, _ := newFakeController(echoHeader)
+	corrupted := concurrentRequests(controller, b.N/4+1)
+	if corrupted != 0 {
+		b.Errorf("%d responses did not match their request", corrupted)
+	}
+}
+
+func TestRequestPartialReads(t *testing.T) {
+	controller, transport := newFakeController(func(frame []byte) []byte {
+		return statusFrame(0x0491, 42, 0)
+	})
+	transport.chunk = 1
+
+	status, err := controller.GetDCStatusUpdate(1)
+	if err != nil {
+		t.Fatalf("GetDCStatusUpdate: %v", err)
+	}
+	if status.Position != 42 {
+		t.Errorf("got position %d, expected 42", status.Position)
+	}
+}
+
+/*
+Transport whose Connect blocks until release is closed, like
+a port waiting for a device that never shows up
+*/
+type hangingTransport struct {
+	fakeTransport
+	release chan struct{}
+}
+
+func (h *hangingTransport) Connect() error {
+	<-h.release
+	return nil
+}
+
+func TestConnectContextTimeout(t *testing.T) {
+	transport := &hangingTransport{release: make(chan struct{})}
+	defer close(transport.release)
+	controller := &protocol.KDC101{Communication: transport}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := controller.ConnectContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, expected context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("ConnectContext returned after %v", elapsed)
+	}
+}
+
+func TestChunkedWrites(t *testing.T) {
+	controller, transport := newFakeController(nil)
+	controller.WriteChunkSize = 4
+
+	if err := controller.MoveAbsoluteCounts(1, 0x12345678); err != nil {
+		t.Fatalf("MoveAbsoluteCounts: %v", err)
+	}
+	if len(transport.written) != 3 {
+		t.Fatalf("got %d writes, expected 3 chunks", len(transport.written))
+	}
+	var frame []byte
+	for _, chunk := range transport.written {
+		frame = append(frame, chunk...)
+	}
+	expected := []byte{0x53, 0x04, 0x06, 0x00, 0xD0, 0x01, 0x01, 0x00, 0x78, 0x56, 0x34, 0x12}
+	if string(frame) != string(expected) {
+		t.Errorf("got % X, expected % X", frame, expected)
+	}
+}
+
+func TestRequestRetries(t *testing.T) {
+	for _, retries := range []int{0, 1} {
+		requests := 0
+		controller, _ := newFakeController(func(frame []byte) []byte {
+			requests++
+			response := echoHeader(frame)
+			if requests == 1 {
+				return response[:5] // Byte dropped on the first answer
+			}
+			return response
+		})
+		controller.RequestRetries = retries
+
+		_, err := controller.IsEnabled(1)
+		if retries == 0 && !errors.Is(err, protocol.ErrInvalidResponseLength) {
+			t.Errorf("without retries: got %v, expected ErrInvalidResponseLength", err)
+		}
+		if retries == 1 && (err != nil || requests != 2) {
+			t.Errorf("with one retry: got %v after %d requests, expected success after 2", err, requests)
+		}
+	}
+}
+
+func TestRequestDataTimeout(t *testing.T) {
+	controller, _ := newFakeController(nil)
+
+	start := time.Now()
+	_, err := controller.RequestDataTimeout(protocol.HeaderMessage{ID: 0x0490, Parameter1: 0x01}, 20*time.Millisecond)
+	if !errors.Is(err, protocol.ErrTimeout) {
+		t.Fatalf("got %v, expected ErrTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed >= protocol.DefaultFrameTimeout {
+		t.Errorf("request gave up after %v, expected about 20ms", elapsed)
+	}
+}
+
+func TestHardwareResponse(t *testing.T) {
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		response := []byte{0x81, 0x00, 0x44, 0x00, 0x81, 0x50, 0x90, 0x04, 0x2B, 0x00}
+		notes := make([]byte, 64)
+		copy(notes, "Hardware Time Out Error")
+		return append(response, notes...)
+	})
+
+	_, err := controller.GetDCStatusUpdate(1)
+	var response *protocol.HardwareResponseError
+	if !errors.As(err, &response) || !errors.Is(err, protocol.ErrHardwareResponse) {
+		t.Fatalf("got %v, expected a HardwareResponseError", err)
+	}
+	if response.MessageID != 0x0490 || response.Code != 43 || response.Notes != "Hardware Time Out Error" {
+		t.Errorf("got %+v, expected code 43 for 0x0490 with its notes", response)
+	}
+}
+
+func TestSetTimeoutsDuringRequests(t *testing.T) {
+	controller := &protocol.KDC101{Communication: unicommtcp.NewTCP(unicommtcp.TCPOptions{})}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := range 100 {
+			controller.SetReadTimeout(time.Duration(i) * time.Millisecond)
+			controller.SetWriteTimeout(time.Duration(i) * time.Millisecond)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 100 {
+			controller.RequestData(protocol.HeaderMessage{ID: 0x0490}) // Fails, nothing is connected
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,173 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrInvalidRecording = fmt.Errorf("invalid recording")
+var ErrReplayMismatch = fmt.Errorf("frame written does not match the recording")
+
+/*
+Starts writing every frame sent and received to w, one line
+per frame with its timestamp, direction and bytes in hex:
+
+	2026-10-16T14:03:12.512345Z tx 110401005001
+	2026-10-16T14:03:12.514801Z rx 120406008150010000000000
+
+Frames are recorded whole even when written in chunks, and
+incomplete frames read before a timeout are recorded as
+received. The recording can be replayed with ReplayTransport
+*/
+func (k *KDC101) StartRecording(w io.Writer) {
+	k.recordMutex.Lock()
+	defer k.recordMutex.Unlock()
+	k.recorder = w
+}
+
+/*
+Stops writing frames to the recording writer
+*/
+func (k *KDC101) StopRecording() {
+	k.recordMutex.Lock()
+	defer k.recordMutex.Unlock()
+	k.recorder = nil
+}
+
+/*
+Writes a frame to the recording, if any. Write errors are
+logged rather than failing the exchange being recorded
+*/
+func (k *KDC101) record(direction string, frame []byte) {
+	k.recordMutex.Lock()
+	defer k.recordMutex.Unlock()
+	if k.recorder == nil {
+		return
+	}
+	timestamp := time.Now().UTC().Format(time.RFC3339Nano)
+	_, err := fmt.Fprintf(k.recorder, "%s %s %s\n", timestamp, direction, hex.EncodeToString(frame))
+	if err != nil {
+		k.logf("recording: %v", err)
+	}
+}
+
+/*
+Transport answering with the frames of a recording made with
+StartRecording. Every frame written must match the next one
+sent in the recording, and makes the frames received after
+it available to Read, so a captured session is reproduced
+deterministically regardless of its original timing
+*/
+type ReplayTransport struct {
+	records []replayRecord
+	next    int
+	pending []byte
+
+	mutex sync.Mutex
+}
+
+type replayRecord struct {
+	sent  bool
+	frame []byte
+}
+
+/*
+Parses a recording and returns a transport replaying it.
+Frames received before the first one sent, e.g. unsolicited
+status updates, are available right away
+*/
+func NewReplayTransport(r io.Reader) (*ReplayTransport, error) {
+	replay := &ReplayTransport{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 || (fields[1] != "tx" && fields[1] != "rx") {
+			return nil, fmt.Errorf("%w: line %d", ErrInvalidRecording, line)
+		}
+		frame, err := hex.DecodeString(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidRecording, line, err)
+		}
+		replay.records = append(replay.records, replayRecord{sent: fields[1] == "tx", frame: frame})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	replay.queueReceived()
+	return replay, nil
+}
+
+func (r *ReplayTransport) Connect() error    { return nil }
+func (r *ReplayTransport) Disconnect() error { return nil }
+func (r *ReplayTransport) IsConnected() bool { return true }
+
+/*
+Reads the received frames made available by previous writes.
+Like a serial port timing out, nothing is returned when
+there are none
+*/
+func (r *ReplayTransport) Read(size uint) ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	n := min(int(size), len(r.pending))
+	data := r.pending[:n]
+	r.pending = r.pending[n:]
+	return data, nil
+}
+
+func (r *ReplayTransport) ReadUntil(delimiter string) ([]byte, error) {
+	return nil, fmt.Errorf("read until is not supported in replay mode")
+}
+
+/*
+Checks the frame against the next one sent in the recording
+and queues the frames received after it. Chunked writes are
+accepted as long as the chunks add up to the recorded frame.
+ErrReplayMismatch is returned when the frame differs or the
+recording has no more frames sent
+*/
+func (r *ReplayTransport) Write(message []byte) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.next >= len(r.records) {
+		return fmt.Errorf("%w: % X written after the end of the recording", ErrReplayMismatch, message)
+	}
+	expected := &r.records[r.next]
+	if !bytes.HasPrefix(expected.frame, message) {
+		return fmt.Errorf("%w: % X written, % X recorded", ErrReplayMismatch, message, expected.frame)
+	}
+	expected.frame = expected.frame[len(message):]
+	if len(expected.frame) > 0 {
+		return nil // Rest of a chunked frame still to come
+	}
+	r.next++
+	r.queueReceived()
+	return nil
+}
+
+/*
+Makes the received frames up to the next one sent available
+*/
+func (r *ReplayTransport) queueReceived() {
+	for r.next < len(r.records) && !r.records[r.next].sent {
+		r.pending = append(r.pending, r.records[r.next].frame...)
+		r.next++
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/devicehub-go/thorlabs-kdc101/protocol"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	recorded, _ := newFakeController(func(frame []byte) []byte {
+		return []byte{0x12, 0x04, 0x06, 0x00, 0x81, 0x50, 0x01, 0x00, 0x10, 0x27, 0x00, 0x00}
+	})
+	var capture bytes.Buffer
+	recorded.StartRecording(&capture)
+	expected, err := recorded.GetPosition(1)
+	if err != nil {
+		t.Fatalf("GetPosition: %v", err)
+	}
+	recorded.StopRecording()
+	if lines := strings.Count(capture.String(), "\n"); lines != 2 {
+		t.Fatalf("got %d recorded frames, expected 2:\n%s", lines, capture.String())
+	}
+
+	transport, err := protocol.NewReplayTransport(bytes.NewReader(capture.Bytes()))
+	if err != nil {
+		t.Fatalf("NewReplayTransport: %v", err)
+	}
+	replayed := &protocol.KDC101{Communication: transport, StageType: "MTS25-Z8", MotorType: "Brushed"}
+	replayed.WriteChunkSize = 2
+	if position, err := replayed.GetPosition(1); err != nil || position != expected {
+		t.Errorf("got %v, %v replayed, expected %v", position, err, expected)
+	}
+	if _, err := replayed.GetPosition(1); !errors.Is(err, protocol.ErrReplayMismatch) {
+		t.Errorf("got %v after the end of the recording, expected ErrReplayMismatch", err)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+import (
+	"fmt"
+	"math"
+)
+
+/*
+Normalization applied to the positions of rotary stages,
+whose counter keeps accumulating past a full turn
+*/
+type AngleWrap uint8
+
+const (
+	WrapNone AngleWrap = 0x00 // Cumulative degrees, e.g. 725°
+	Wrap360  AngleWrap = 0x01 // Degrees in [0, 360)
+	Wrap180  AngleWrap = 0x02 // Degrees in [-180, 180)
+)
+
+var ErrNotRotaryStage = fmt.Errorf("stage is not a rotary stage")
+
+/*
+Tells whether the configured stage is a rotation mount,
+i.e. its travel range is a full turn
+*/
+func (k *KDC101) IsRotary() bool {
+	low, high := k.TravelRange()
+	return low == 0 && high == 360
+}
+
+/*
+Applies the configured angle wrap to a position of a rotary
+stage. Positions of linear stages are returned unchanged
+*/
+func (k *KDC101) wrapAngle(position float64) float64 {
+	if !k.IsRotary() {
+		return position
+	}
+	switch k.AngleWrap {
+	case Wrap360:
+		return math.Mod(math.Mod(position, 360)+360, 360)
+	case Wrap180:
+		return math.Mod(math.Mod(position+180, 360)+360, 360) - 180
+	}
+	return position
+}
+
+/*
+Rotates a rotary stage to the angle in degrees along the
+shorter direction, e.g. from 350° to 10° by moving +20°
+rather than -340°. The angle may be given in any turn
+*/
+func (k *KDC101) MoveToAngleShortestPath(channel uint8, degrees float64) error {
+	if _, err := k.channelBitmask(channel); err != nil {
+		return err
+	}
+	if !k.IsRotary() {
+		return fmt.Errorf("%w: %s", ErrNotRotaryStage, k.StageType)
+	}
+	position, err := k.getRawPosition(channel)
+	if err != nil {
+		return err
+	}
+	delta := math.Mod(math.Mod(degrees-position+180, 360)+360, 360) - 180
+	return k.MoveRelativeDistance(channel, delta)
+}
@@ -0,0 +1,129 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+/*
+Distance of the test move done by SelfTest, in encoder
+counts (about 15 µm on a MTS25-Z8)
+*/
+const SelfTestCounts = 500
+
+var ErrSelfTestFailed = fmt.Errorf("self test failed")
+
+/*
+Outcome of one check of SelfTest. Err holds the reason of a
+failed check
+*/
+type SelfTestCheck struct {
+	Name   string
+	Passed bool
+	Err    error
+}
+
+type SelfTestReport struct {
+	Checks []SelfTestCheck
+}
+
+/*
+Returns true if every check of the report passed
+*/
+func (r SelfTestReport) Passed() bool {
+	for _, check := range r.Checks {
+		if !check.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Checks that the controller and stage are working: connects
+if needed, reads the hardware information, checks that the
+channel is enabled and reports no fault, then moves forward
+by SelfTestCounts, confirms the position changed and moves
+back. Checks after a failed one are skipped when they would
+be unsafe, and the stage is moved back to its starting
+position whenever the test move was made. ErrSelfTestFailed
+is returned, naming the failed checks, if any check failed
+*/
+func (k *KDC101) SelfTest(channel uint8) (SelfTestReport, error) {
+	var report SelfTestReport
+	check := func(name string, err error) bool {
+		report.Checks = append(report.Checks, SelfTestCheck{Name: name, Passed: err == nil, Err: err})
+		return err == nil
+	}
+	if _, err := k.channelBitmask(channel); err != nil {
+		return report, err
+	}
+
+	if !k.IsConnected() {
+		if !check("connect", k.Connect()) {
+			return report, report.failure()
+		}
+	}
+	_, err := k.GetInformation()
+	if !check("hardware information", err) {
+		return report, report.failure()
+	}
+	enabled, err := k.IsEnabled(channel)
+	if err == nil && !enabled {
+		err = fmt.Errorf("channel %d is disabled", channel)
+	}
+	check("channel enabled", err)
+	faults := k.checkFaults(channel)
+	check("no faults", faults)
+	if err != nil || faults != nil {
+		return report, report.failure()
+	}
+
+	start, err := k.GetDCStatusUpdate(channel)
+	if !check("read position", err) {
+		return report, report.failure()
+	}
+	err = k.MoveRelativeCounts(channel, SelfTestCounts)
+	if err == nil {
+		err = k.waitForStop(context.Background(), channel, DefaultMoveTimeout)
+	}
+	if err == nil {
+		var moved DCStatusUpdate
+		moved, err = k.GetDCStatusUpdate(channel)
+		if err == nil && moved.Position == start.Position {
+			err = fmt.Errorf("position did not change")
+		}
+	}
+	check("test move", err)
+
+	err = k.MoveAbsoluteCounts(channel, start.Position)
+	if err == nil {
+		err = k.waitForStop(context.Background(), channel, DefaultMoveTimeout)
+	}
+	check("move back", err)
+	return report, report.failure()
+}
+
+/*
+Returns ErrSelfTestFailed naming the failed checks, or nil
+if every check passed
+*/
+func (r SelfTestReport) failure() error {
+	var failed []string
+	for _, check := range r.Checks {
+		if !check.Passed {
+			failed = append(failed, check.Name)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrSelfTestFailed, strings.Join(failed, ", "))
+}
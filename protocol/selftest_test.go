@@ -0,0 +1,47 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol_test
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+/*
+Emulates a stage that moves instantly to the commanded
+position and reports no fault
+*/
+func instantStage(position *int32) func([]byte) []byte {
+	return func(frame []byte) []byte {
+		switch uint16(frame[0]) | uint16(frame[1])<<8 {
+		case 0x0005:
+			return append([]byte{0x06, 0x00, 84, 0x00, 0x81, 0x50}, make([]byte, 84)...)
+		case 0x0211:
+			return []byte{0x12, 0x02, 0x01, 0x01, 0x01, 0x50}
+		case 0x0448:
+			*position += int32(binary.LittleEndian.Uint32(frame[8:12]))
+		case 0x0453:
+			*position = int32(binary.LittleEndian.Uint32(frame[8:12]))
+		case 0x0490:
+			return statusFrame(0x0491, *position, 0x2000) // Settled
+		}
+		return nil
+	}
+}
+
+func TestSelfTestReturnsToStart(t *testing.T) {
+	position := int32(1234)
+	controller, _ := newFakeController(instantStage(&position))
+
+	report, err := controller.SelfTest(1)
+	if err != nil || !report.Passed() {
+		t.Fatalf("SelfTest: got %v with %+v, expected every check to pass", err, report.Checks)
+	}
+	if position != 1234 {
+		t.Errorf("stage left at %d counts, expected 1234", position)
+	}
+}
@@ -1,40 +1,77 @@
 /*
 Author: Leonardo Rossi Leao
 Created at: September 26th, 2025
-Last update: September 26th, 2025
+Last update: October 16th, 2026
 */
 
 package protocol
 
 import (
-	"github.com/devicehub-go/thorlabs-kdc101/internal/utils"
+	"errors"
+	"fmt"
+	"math"
 )
 
 type VelocityProfile struct {
-	MinVelocity float64
-	MaxVelocity float64
-	Acceleration float64
+	MinVelocity  float64 `json:"min_velocity"` // mm/s
+	MaxVelocity  float64 `json:"max_velocity"` // mm/s
+	Acceleration float64 `json:"acceleration"` // mm/s²
 }
 
 type JogParameters struct {
-	Mode         uint16
-	StepSize     float64
-	MinVelocity  float64
-	Acceleration float64
-	MaxVelocity  float64
-	StopMode     uint16
+	Mode         uint16      `json:"mode"`
+	StepSize     float64     `json:"step_size"`    // mm
+	MinVelocity  float64     `json:"min_velocity"` // mm/s
+	Acceleration float64     `json:"acceleration"` // mm/s²
+	MaxVelocity  float64     `json:"max_velocity"` // mm/s
+	StopMode     JogStopMode `json:"stop_mode"`
+}
+
+type HomeParameters struct {
+	Direction      uint16  `json:"direction"`
+	LimitSwitch    uint16  `json:"limit_switch"`
+	Velocity       float64 `json:"velocity"`        // mm/s
+	OffsetDistance float64 `json:"offset_distance"` // mm
+}
+
+type LimitSwitchParameters struct {
+	CWHardLimit   uint16  `json:"cw_hard_limit"`
+	CCWHardLimit  uint16  `json:"ccw_hard_limit"`
+	CWSoftLimit   float64 `json:"cw_soft_limit"`  // mm
+	CCWSoftLimit  float64 `json:"ccw_soft_limit"` // mm
+	SoftLimitMode uint16  `json:"soft_limit_mode"`
 }
 
+/*
+How a jog move ends, stored in the jog parameters. Distinct
+from StopMode, which is passed to Stop
+*/
+type JogStopMode uint16
+
+const (
+	JogModeContinuous uint16 = 0x01
+	JogModeSingleStep uint16 = 0x02
+
+	JogStopImmediate JogStopMode = 0x01
+	JogStopProfiled  JogStopMode = 0x02
+)
+
+var ErrInvalidJogMode = fmt.Errorf("invalid jog mode")
+var ErrInvalidJogStopMode = fmt.Errorf("invalid jog stop mode")
+var ErrInvalidBowIndex = fmt.Errorf("bow index must be between 0 and 18")
+var ErrReadbackMismatch = fmt.Errorf("parameter read back differs from the value set")
+
 /*
 Sent to enable or disable the specified drive channel.
 */
 func (k *KDC101) Enable(channel uint8, enable bool) error {
-	if channel != 1 {
-		return ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
 	}
 	msg := HeaderMessage{
-		ID:          0x0224,
-		Parameter1:  byte(1 << (channel - 1)),
+		ID:          msgModSetChanEnableState,
+		Parameter1:  ident,
 		Destination: GenericUnit,
 		Source:      Host,
 	}
@@ -47,15 +84,20 @@ func (k *KDC101) Enable(channel uint8, enable bool) error {
 }
 
 /*
-Get the enabled state of a channel
+Get the enabled state of a channel, as set by Enable. This
+queries the channel enable state and is distinct from the
+IsEnabled status bit, which reports whether the servo loop is
+active; the two can disagree, e.g. while a fault holds the
+loop off on an enabled channel
 */
 func (k *KDC101) IsEnabled(channel uint8) (bool, error) {
-	if channel != 1 {
-		return false, ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return false, err
 	}
 	response, err := k.RequestHeaderOnly(HeaderMessage{
-		ID:          0x0211,
-		Parameter1:  byte(1 << (channel - 1)),
+		ID:          msgModReqChanEnableState,
+		Parameter1:  ident,
 		Destination: GenericUnit,
 		Source:      Host,
 	})
@@ -67,26 +109,35 @@ func (k *KDC101) IsEnabled(channel uint8) (bool, error) {
 
 /*
 Sets trapezoidal velocity parameters for the specified
-motor channel.
+motor channel. The controller applies them to a move in
+progress as well. The profile is not changed while a
+multi-command sequence such as MoveAbsoluteWithProfile runs
 */
 func (k *KDC101) SetTrapezoidalVelocity(channel uint8, profile VelocityProfile) error {
-	if channel != 1 {
-		return ErrChannelNotSupported
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	return k.setTrapezoidalVelocity(channel, profile)
+}
+
+func (k *KDC101) setTrapezoidalVelocity(channel uint8, profile VelocityProfile) error {
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
 	}
 	minVel := k.VelocityToCounts(profile.MinVelocity)
 	accel := k.AccelerationToCounts(profile.Acceleration)
 	maxVel := k.VelocityToCounts(profile.MaxVelocity)
 
 	data := []byte{
-		byte(1 << (channel - 1)),
+		ident,
 		0x00,
 	}
-	data = append(data, utils.DwordToBytes(minVel)...)
-	data = append(data, utils.DwordToBytes(accel)...)
-	data = append(data, utils.DwordToBytes(maxVel)...)
+	data = append(data, k.codec().DwordToBytes(minVel)...)
+	data = append(data, k.codec().DwordToBytes(accel)...)
+	data = append(data, k.codec().DwordToBytes(maxVel)...)
 
 	return k.WriteData(DataMessage{
-		ID:          0x0413,
+		ID:          msgMotSetVelParams,
 		Data:        data,
 		DataLength:  uint16(len(data)),
 		Destination: GenericUnit,
@@ -99,27 +150,31 @@ Get trapezoidal velocity parameters for the specified
 motor channel
 */
 func (k *KDC101) GetTrapezoidalVelocity(channel uint8) (VelocityProfile, error) {
-	if channel != 1 {
-		return VelocityProfile{}, ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return VelocityProfile{}, err
 	}
 	response, err := k.RequestData(HeaderMessage{
-		ID:          0x0414,
-		Parameter1:  byte(1 << (channel - 1)),
+		ID:          msgMotReqVelParams,
+		Parameter1:  ident,
 		Destination: GenericUnit,
 		Source:      Host,
 	})
 	if err != nil {
 		return VelocityProfile{}, err
 	}
+	if err := expectResponse(response, msgMotGetVelParams); err != nil {
+		return VelocityProfile{}, err
+	}
 
 	data := response.Data
 	if len(data) < 14 {
 		return VelocityProfile{}, ErrInvalidResponseLength
 	}
 
-	minVel := k.CountsToVelocity(utils.BytesToDword(data[2:6]))
-	accel := k.CountsToAcceleration(utils.BytesToLong(data[6:10]))
-	maxVel := k.CountsToVelocity(utils.BytesToDword(data[10:14]))
+	minVel := k.CountsToVelocity(k.codec().BytesToDword(data[2:6]))
+	accel := k.CountsToAcceleration(k.codec().BytesToLong(data[6:10]))
+	maxVel := k.CountsToVelocity(k.codec().BytesToDword(data[10:14]))
 
 	return VelocityProfile{
 		MinVelocity: minVel,
@@ -128,12 +183,87 @@ func (k *KDC101) GetTrapezoidalVelocity(channel uint8) (VelocityProfile, error)
 	}, nil
 }
 
+/*
+Sets the trapezoidal velocity parameters and reads them back,
+returning ErrReadbackMismatch if a value read back differs
+from the quantized one set by more than the tolerance, e.g.
+because the firmware clamped it
+*/
+func (k *KDC101) SetTrapezoidalVelocityVerified(channel uint8, profile VelocityProfile, tolerance float64) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if err := k.setTrapezoidalVelocity(channel, profile); err != nil {
+		return err
+	}
+	readback, err := k.GetTrapezoidalVelocity(channel)
+	if err != nil {
+		return err
+	}
+	expected := k.SnapToAchievable(profile)
+	return errors.Join(
+		checkReadback("minimum velocity", expected.MinVelocity, readback.MinVelocity, tolerance),
+		checkReadback("maximum velocity", expected.MaxVelocity, readback.MaxVelocity, tolerance),
+		checkReadback("acceleration", expected.Acceleration, readback.Acceleration, tolerance),
+	)
+}
+
+/*
+Sets the jog parameters and reads them back like
+SetTrapezoidalVelocityVerified. The modes must read back
+exactly
+*/
+func (k *KDC101) SetJogParametersVerified(channel uint8, params JogParameters, tolerance float64) error {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if err := k.SetJogParameters(channel, params); err != nil {
+		return err
+	}
+	readback, err := k.GetJogParameters(channel)
+	if err != nil {
+		return err
+	}
+	velocity := k.SnapToAchievable(VelocityProfile{
+		MinVelocity:  params.MinVelocity,
+		MaxVelocity:  params.MaxVelocity,
+		Acceleration: params.Acceleration,
+	})
+	return errors.Join(
+		checkReadback("mode", float64(params.Mode), float64(readback.Mode), 0),
+		checkReadback("step size", k.CountsToPosition(k.PositionToCounts(params.StepSize)), readback.StepSize, tolerance),
+		checkReadback("minimum velocity", velocity.MinVelocity, readback.MinVelocity, tolerance),
+		checkReadback("maximum velocity", velocity.MaxVelocity, readback.MaxVelocity, tolerance),
+		checkReadback("acceleration", velocity.Acceleration, readback.Acceleration, tolerance),
+		checkReadback("stop mode", float64(params.StopMode), float64(readback.StopMode), 0),
+	)
+}
+
+/*
+Returns ErrReadbackMismatch naming the parameter if the
+value read back is further than the tolerance from the set
+one
+*/
+func checkReadback(name string, set, read, tolerance float64) error {
+	if math.Abs(read-set) > tolerance {
+		return fmt.Errorf("%w: %s set to %g, read back %g", ErrReadbackMismatch, name, set, read)
+	}
+	return nil
+}
+
 /*
 Set the velocity jog paramaters for the specified channel.
 */
 func (k *KDC101) SetJogParameters(channel uint8, params JogParameters) error {
-	if channel != 1 {
-		return ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
+	}
+	if params.Mode != JogModeContinuous && params.Mode != JogModeSingleStep {
+		return fmt.Errorf("%w: %d", ErrInvalidJogMode, params.Mode)
+	}
+	if params.StopMode != JogStopImmediate && params.StopMode != JogStopProfiled {
+		return fmt.Errorf("%w: %d", ErrInvalidJogStopMode, params.StopMode)
 	}
 	stepSize := k.PositionToCounts(params.StepSize)
 	minVel := k.VelocityToCounts(params.MinVelocity)
@@ -141,18 +271,18 @@ func (k *KDC101) SetJogParameters(channel uint8, params JogParameters) error {
 	maxVel := k.VelocityToCounts(params.MaxVelocity)
 
 	data := []byte{
-		byte(1 << (channel - 1)),
+		ident,
 		0x00,
 	}
-	data = append(data, utils.WordToBytes(params.Mode)...)
-	data = append(data, utils.LongToBytes(stepSize)...)
-	data = append(data, utils.DwordToBytes(minVel)...)
-	data = append(data, utils.DwordToBytes(accel)...)
-	data = append(data, utils.DwordToBytes(maxVel)...)
-	data = append(data, utils.WordToBytes(params.StopMode)...)
+	data = append(data, k.codec().WordToBytes(params.Mode)...)
+	data = append(data, k.codec().LongToBytes(stepSize)...)
+	data = append(data, k.codec().DwordToBytes(minVel)...)
+	data = append(data, k.codec().DwordToBytes(accel)...)
+	data = append(data, k.codec().DwordToBytes(maxVel)...)
+	data = append(data, k.codec().WordToBytes(uint16(params.StopMode))...)
 
 	return k.WriteData(DataMessage{
-		ID:          0x0416,
+		ID:          msgMotSetJogParams,
 		Data:        data,
 		DataLength:  uint16(len(data)),
 		Destination: GenericUnit,
@@ -164,30 +294,34 @@ func (k *KDC101) SetJogParameters(channel uint8, params JogParameters) error {
 Get the jog parameters for the specified channel.
 */
 func (k *KDC101) GetJogParameters(channel uint8) (JogParameters, error) {
-	if channel != 1 {
-		return JogParameters{}, ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return JogParameters{}, err
 	}
 	response, err := k.RequestData(HeaderMessage{
-		ID:          0x0417,
-		Parameter1:  byte(1 << (channel - 1)),
+		ID:          msgMotReqJogParams,
+		Parameter1:  ident,
 		Destination: GenericUnit,
 		Source:      Host,
 	})
 	if err != nil {
 		return JogParameters{}, err
 	}
+	if err := expectResponse(response, msgMotGetJogParams); err != nil {
+		return JogParameters{}, err
+	}
 
 	data := response.Data
 	if len(data) < 22 {
 		return JogParameters{}, ErrInvalidResponseLength
 	}
 
-	mode := utils.BytesToWord(data[2:4])
-	stepSize := k.CountsToPosition(utils.BytesToLong(data[4:8]))
-	minVel := k.CountsToVelocity(utils.BytesToDword(data[8:12]))
-	accel := k.CountsToAcceleration(utils.BytesToLong(data[12:16]))
-	maxVel := k.CountsToVelocity(utils.BytesToDword(data[16:20]))
-	stopMode := utils.BytesToWord(data[20:22])
+	mode := k.codec().BytesToWord(data[2:4])
+	stepSize := k.CountsToPosition(k.codec().BytesToLong(data[4:8]))
+	minVel := k.CountsToVelocity(k.codec().BytesToDword(data[8:12]))
+	accel := k.CountsToAcceleration(k.codec().BytesToLong(data[12:16]))
+	maxVel := k.CountsToVelocity(k.codec().BytesToDword(data[16:20]))
+	stopMode := JogStopMode(k.codec().BytesToWord(data[20:22]))
 
 	return JogParameters{
 		Mode:         mode,
@@ -199,51 +333,130 @@ func (k *KDC101) GetJogParameters(channel uint8) (JogParameters, error) {
 	}, nil
 }
 
+/*
+Sets only the jog step size in millimeters. The other jog
+parameters are read back from the device and rewritten
+unchanged, so velocities are not disturbed by a round trip
+through the unit conversions
+*/
+func (k *KDC101) SetJogStep(channel uint8, step float64) error {
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
+	}
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	response, err := k.RequestData(HeaderMessage{
+		ID:          msgMotReqJogParams,
+		Parameter1:  ident,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+	if err != nil {
+		return err
+	}
+	if err := expectResponse(response, msgMotGetJogParams); err != nil {
+		return err
+	}
+	if len(response.Data) < 22 {
+		return ErrInvalidResponseLength
+	}
+	data := append([]byte{}, response.Data[:4]...)
+	data = append(data, k.codec().LongToBytes(k.PositionToCounts(step))...)
+	data = append(data, response.Data[8:22]...)
+
+	return k.WriteData(DataMessage{
+		ID:          msgMotSetJogParams,
+		Data:        data,
+		DataLength:  uint16(len(data)),
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+}
+
+/*
+Get the jog step size in millimeters
+*/
+func (k *KDC101) GetJogStep(channel uint8) (float64, error) {
+	params, err := k.GetJogParameters(channel)
+	if err != nil {
+		return 0, err
+	}
+	return params.StepSize, nil
+}
+
 /*
 Sets the relative move distance that will be used
 the next time that a relative move is initiated
 */
 func (k *KDC101) SetRelativeMoveDistance(channel uint8, distance float64) error {
-	if channel != 1 {
-		return ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
 	}
 	var counts int32 = k.PositionToCounts(distance)
 	data := []byte{
-		byte(1 << (channel - 1)),
+		ident,
 		0x00,
 	}
-	data = append(data, utils.LongToBytes(counts)...)
-	return k.WriteData(DataMessage{
-		ID:          0x0445,
+	data = append(data, k.codec().LongToBytes(counts)...)
+	err = k.WriteData(DataMessage{
+		ID:          msgMotSetMoveRelParams,
 		Data:        data,
 		DataLength:  uint16(len(data)),
 		Destination: GenericUnit,
 		Source:      Host,
 	})
+	if err != nil {
+		return err
+	}
+	k.relativeStep = &distance
+	return nil
 }
 
 /*
 Gets the target distance for the next relative move
 */
 func (k *KDC101) GetRelativeMoveDistance(channel uint8) (float64, error) {
-	if channel != 1 {
-		return 0, ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return 0, err
 	}
 	response, err := k.RequestData(HeaderMessage{
-		ID:          0x0446,
-		Parameter1:  byte(1 << (channel - 1)),
+		ID:          msgMotReqMoveRelParams,
+		Parameter1:  ident,
 		Destination: GenericUnit,
 		Source:      Host,
 	})
 	if err != nil {
 		return 0, err
 	}
+	if err := expectResponse(response, msgMotGetMoveRelParams); err != nil {
+		return 0, err
+	}
 	data := response.Data
 	if len(data) < 6 {
 		return 0, ErrInvalidResponseLength
 	}
-	counts := utils.BytesToLong(data[2:6])
-	return k.CountsToPosition(int32(counts)), nil
+	distance := k.CountsToPosition(k.codec().BytesToLong(data[2:6]))
+	k.relativeStep = &distance
+	return distance, nil
+}
+
+/*
+Sets the step size used by StepForward and StepReverse. The
+step is stored on the device as the relative move distance
+*/
+func (k *KDC101) SetRelativeStepSize(channel uint8, step float64) error {
+	return k.SetRelativeMoveDistance(channel, step)
+}
+
+/*
+Gets the step size used by StepForward and StepReverse
+*/
+func (k *KDC101) GetRelativeStepSize(channel uint8) (float64, error) {
+	return k.GetRelativeMoveDistance(channel)
 }
 
 /*
@@ -252,17 +465,21 @@ The target position is used the next time that an absolute
 move is initiated.
 */
 func (k *KDC101) SetAbsoluteMoveDistance(channel uint8, position float64) error {
-	if channel != 1 {
-		return ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
+	}
+	if err := k.validateTravel(position); err != nil {
+		return err
 	}
-	var counts int32 = k.PositionToCounts(position)
+	var counts int32 = k.clampCounts(k.PositionToCounts(position))
 	data := []byte{
-		byte(1 << (channel - 1)),
+		ident,
 		0x00,
 	}
-	data = append(data, utils.LongToBytes(counts)...)
+	data = append(data, k.codec().LongToBytes(counts)...)
 	return k.WriteData(DataMessage{
-		ID:          0x0450,
+		ID:          msgMotSetMoveAbsParams,
 		Data:        data,
 		DataLength:  uint16(len(data)),
 		Destination: GenericUnit,
@@ -274,22 +491,307 @@ func (k *KDC101) SetAbsoluteMoveDistance(channel uint8, position float64) error
 Gets the target position for the next absolute move
 */
 func (k *KDC101) GetAbsoluteMoveDistance(channel uint8) (float64, error) {
-	if channel != 1 {
-		return 0, ErrChannelNotSupported
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return 0, err
+	}
+	response, err := k.RequestData(HeaderMessage{
+		ID:          msgMotReqMoveAbsParams,
+		Parameter1:  ident,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := expectResponse(response, msgMotGetMoveAbsParams); err != nil {
+		return 0, err
+	}
+	data := response.Data
+	if len(data) < 6 {
+		return 0, ErrInvalidResponseLength
+	}
+	return k.CountsToPosition(k.codec().BytesToLong(data[2:6])), nil
+}
+
+/*
+Sets the home parameters for the specified channel
+*/
+func (k *KDC101) SetHomeParameters(channel uint8, params HomeParameters) error {
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
+	}
+	velocity := k.VelocityToCounts(params.Velocity)
+	offset := k.PositionToCounts(params.OffsetDistance)
+
+	data := []byte{
+		ident,
+		0x00,
+	}
+	data = append(data, k.codec().WordToBytes(params.Direction)...)
+	data = append(data, k.codec().WordToBytes(params.LimitSwitch)...)
+	data = append(data, k.codec().DwordToBytes(velocity)...)
+	data = append(data, k.codec().LongToBytes(offset)...)
+
+	return k.WriteData(DataMessage{
+		ID:          msgMotSetHomeParams,
+		Data:        data,
+		DataLength:  uint16(len(data)),
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+}
+
+/*
+Gets the home parameters for the specified channel
+*/
+func (k *KDC101) GetHomeParameters(channel uint8) (HomeParameters, error) {
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return HomeParameters{}, err
+	}
+	response, err := k.RequestData(HeaderMessage{
+		ID:          msgMotReqHomeParams,
+		Parameter1:  ident,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+	if err != nil {
+		return HomeParameters{}, err
+	}
+	if err := expectResponse(response, msgMotGetHomeParams); err != nil {
+		return HomeParameters{}, err
+	}
+
+	data := response.Data
+	if len(data) < 14 {
+		return HomeParameters{}, ErrInvalidResponseLength
+	}
+
+	return HomeParameters{
+		Direction:      k.codec().BytesToWord(data[2:4]),
+		LimitSwitch:    k.codec().BytesToWord(data[4:6]),
+		Velocity:       k.CountsToVelocity(k.codec().BytesToDword(data[6:10])),
+		OffsetDistance: k.CountsToPosition(k.codec().BytesToLong(data[10:14])),
+	}, nil
+}
+
+/*
+Shifts the home position of the specified channel by setting
+only the home offset distance in millimeters. The other home
+parameters are read from the device and written back as raw
+counts, so they are not altered by unit conversions
+*/
+func (k *KDC101) SetHomeOffset(channel uint8, offset float64) error {
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
+	}
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	response, err := k.RequestData(HeaderMessage{
+		ID:          msgMotReqHomeParams,
+		Parameter1:  ident,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+	if err != nil {
+		return err
+	}
+	if err := expectResponse(response, msgMotGetHomeParams); err != nil {
+		return err
+	}
+	if len(response.Data) < 14 {
+		return ErrInvalidResponseLength
+	}
+	data := append([]byte{}, response.Data[:10]...)
+	data = append(data, k.codec().LongToBytes(k.PositionToCounts(offset))...)
+
+	return k.WriteData(DataMessage{
+		ID:          msgMotSetHomeParams,
+		Data:        data,
+		DataLength:  uint16(len(data)),
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+}
+
+/*
+Sets the backlash distance used on moves for the
+specified channel
+*/
+func (k *KDC101) SetBacklashDistance(channel uint8, distance float64) error {
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
+	}
+	var counts int32 = k.PositionToCounts(distance)
+	data := []byte{
+		ident,
+		0x00,
+	}
+	data = append(data, k.codec().LongToBytes(counts)...)
+	return k.WriteData(DataMessage{
+		ID:          msgMotSetGenMoveParams,
+		Data:        data,
+		DataLength:  uint16(len(data)),
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+}
+
+/*
+Gets the backlash distance for the specified channel
+*/
+func (k *KDC101) GetBacklashDistance(channel uint8) (float64, error) {
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return 0, err
 	}
 	response, err := k.RequestData(HeaderMessage{
-		ID:          0x0451,
-		Parameter1:  byte(1 << (channel - 1)),
+		ID:          msgMotReqGenMoveParams,
+		Parameter1:  ident,
 		Destination: GenericUnit,
 		Source:      Host,
 	})
 	if err != nil {
 		return 0, err
 	}
+	if err := expectResponse(response, msgMotGetGenMoveParams); err != nil {
+		return 0, err
+	}
 	data := response.Data
 	if len(data) < 6 {
 		return 0, ErrInvalidResponseLength
 	}
-	counts := utils.BytesToLong(data[2:6])
-	return k.CountsToPosition(int32(counts)), nil
-}
\ No newline at end of file
+	return k.CountsToPosition(k.codec().BytesToLong(data[2:6])), nil
+}
+
+/*
+Sets the limit switch parameters for the specified channel
+*/
+func (k *KDC101) SetLimitSwitchParameters(channel uint8, params LimitSwitchParameters) error {
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
+	}
+	cwSoft := k.PositionToCounts(params.CWSoftLimit)
+	ccwSoft := k.PositionToCounts(params.CCWSoftLimit)
+
+	data := []byte{
+		ident,
+		0x00,
+	}
+	data = append(data, k.codec().WordToBytes(params.CWHardLimit)...)
+	data = append(data, k.codec().WordToBytes(params.CCWHardLimit)...)
+	data = append(data, k.codec().LongToBytes(cwSoft)...)
+	data = append(data, k.codec().LongToBytes(ccwSoft)...)
+	data = append(data, k.codec().WordToBytes(params.SoftLimitMode)...)
+
+	return k.WriteData(DataMessage{
+		ID:          msgMotSetLimSwitchParams,
+		Data:        data,
+		DataLength:  uint16(len(data)),
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+}
+
+/*
+Gets the limit switch parameters for the specified channel
+*/
+func (k *KDC101) GetLimitSwitchParameters(channel uint8) (LimitSwitchParameters, error) {
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return LimitSwitchParameters{}, err
+	}
+	response, err := k.RequestData(HeaderMessage{
+		ID:          msgMotReqLimSwitchParams,
+		Parameter1:  ident,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+	if err != nil {
+		return LimitSwitchParameters{}, err
+	}
+	if err := expectResponse(response, msgMotGetLimSwitchParams); err != nil {
+		return LimitSwitchParameters{}, err
+	}
+
+	data := response.Data
+	if len(data) < 16 {
+		return LimitSwitchParameters{}, ErrInvalidResponseLength
+	}
+
+	return LimitSwitchParameters{
+		CWHardLimit:   k.codec().BytesToWord(data[2:4]),
+		CCWHardLimit:  k.codec().BytesToWord(data[4:6]),
+		CWSoftLimit:   k.CountsToPosition(k.codec().BytesToLong(data[6:10])),
+		CCWSoftLimit:  k.CountsToPosition(k.codec().BytesToLong(data[10:14])),
+		SoftLimitMode: k.codec().BytesToWord(data[14:16]),
+	}, nil
+}
+
+/*
+Sets the bow index of the specified channel, which selects
+a trapezoidal profile (0) or an S-curve profile limiting the
+jerk to 2^(index-1) (1 to 18). The APT protocol does not list
+the bow index messages for the KDC101, so support is checked
+by reading the index first
+*/
+func (k *KDC101) SetBowIndex(channel uint8, index uint16) error {
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return err
+	}
+	if index > 18 {
+		return fmt.Errorf("%w: %d", ErrInvalidBowIndex, index)
+	}
+	if _, err := k.GetBowIndex(channel); err != nil {
+		return err
+	}
+	data := []byte{
+		ident,
+		0x00,
+	}
+	data = append(data, k.codec().WordToBytes(index)...)
+	return k.WriteData(DataMessage{
+		ID:          msgMotSetBowIndex,
+		Data:        data,
+		DataLength:  uint16(len(data)),
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+}
+
+/*
+Gets the bow index of the specified channel. Returns
+ErrUnsupportedByFirmware if the controller does not answer
+*/
+func (k *KDC101) GetBowIndex(channel uint8) (uint16, error) {
+	ident, err := k.channelBitmask(channel)
+	if err != nil {
+		return 0, err
+	}
+	response, err := k.RequestData(HeaderMessage{
+		ID:          msgMotReqBowIndex,
+		Parameter1:  ident,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+	if err == nil && (response.ID != msgMotGetBowIndex || len(response.Data) < 4) {
+		err = ErrInvalidResponseLength
+	}
+	if errors.Is(err, ErrInvalidResponseLength) {
+		if version, verr := k.GetFirmwareVersion(); verr == nil {
+			return 0, fmt.Errorf("%w: bow index on firmware %s", ErrUnsupportedByFirmware, version)
+		}
+		return 0, fmt.Errorf("%w: bow index", ErrUnsupportedByFirmware)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return k.codec().BytesToWord(response.Data[2:4]), nil
+}
@@ -0,0 +1,219 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/devicehub-go/thorlabs-kdc101/protocol"
+)
+
+/*
+Emulates a parameter block: the data of the set message is
+stored and sent back when the matching request arrives
+*/
+func storeParameters(set uint16) func([]byte) []byte {
+	var stored []byte
+	return func(frame []byte) []byte {
+		id := uint16(frame[0]) | uint16(frame[1])<<8
+		switch id {
+		case set:
+			stored = append([]byte{}, frame[6:]...)
+		case set + 1:
+			response := []byte{byte(set + 2), byte((set + 2) >> 8), byte(len(stored)), 0x00, byte(protocol.Host) | 0x80, byte(protocol.GenericUnit)}
+			return append(response, stored...)
+		}
+		return nil
+	}
+}
+
+func TestRelativeMoveDistanceSign(t *testing.T) {
+	controller, _ := newFakeController(storeParameters(0x0445))
+	resolution := 1 / protocol.StageScalingFactor[controller.StageType]
+
+	for _, distance := range []float64{-1.5, -0.001, 2.0} {
+		if err := controller.SetRelativeMoveDistance(1, distance); err != nil {
+			t.Fatalf("SetRelativeMoveDistance(%v): %v", distance, err)
+		}
+		got, err := controller.GetRelativeMoveDistance(1)
+		if err != nil {
+			t.Fatalf("GetRelativeMoveDistance: %v", err)
+		}
+		if math.Abs(got-distance) > resolution {
+			t.Errorf("distance %v read back as %v", distance, got)
+		}
+	}
+}
+
+func TestSetHomeOffsetKeepsParameters(t *testing.T) {
+	controller, _ := newFakeController(storeParameters(0x0440))
+	params := protocol.HomeParameters{Direction: 2, LimitSwitch: 1, Velocity: 1.234567, OffsetDistance: 0.5}
+	if err := controller.SetHomeParameters(1, params); err != nil {
+		t.Fatalf("SetHomeParameters: %v", err)
+	}
+	before, err := controller.GetHomeParameters(1)
+	if err != nil {
+		t.Fatalf("GetHomeParameters: %v", err)
+	}
+	if err := controller.SetHomeOffset(1, 0.3); err != nil {
+		t.Fatalf("SetHomeOffset: %v", err)
+	}
+	after, err := controller.GetHomeParameters(1)
+	if err != nil {
+		t.Fatalf("GetHomeParameters: %v", err)
+	}
+	resolution := 1 / protocol.StageScalingFactor[controller.StageType]
+	if math.Abs(after.OffsetDistance-0.3) > resolution {
+		t.Errorf("got offset %v, expected 0.3", after.OffsetDistance)
+	}
+	after.OffsetDistance = before.OffsetDistance
+	if after != before {
+		t.Errorf("got %+v, expected only the offset to change from %+v", after, before)
+	}
+}
+
+func TestInvalidJogStopMode(t *testing.T) {
+	controller := &protocol.KDC101{StageType: "MTS25-Z8", MotorType: "Brushed"}
+
+	for _, mode := range []protocol.JogStopMode{0x00, 0x03, 0xFFFF} {
+		params := protocol.JogParameters{Mode: protocol.JogModeSingleStep, StopMode: mode}
+		if err := controller.SetJogParameters(1, params); !errors.Is(err, protocol.ErrInvalidJogStopMode) {
+			t.Errorf("SetJogParameters stop mode %d: got %v, expected ErrInvalidJogStopMode", mode, err)
+		}
+	}
+}
+
+func TestEnableFrames(t *testing.T) {
+	controller, transport := newFakeController(nil)
+	if err := controller.Enable(1, true); err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	if err := controller.Enable(1, false); err != nil {
+		t.Fatalf("Disable: %v", err)
+	}
+	// MGMSG_MOD_SET_CHANENABLESTATE (0x0210), channel 1, enable then disable
+	expected := [][]byte{
+		{0x10, 0x02, 0x01, 0x01, 0x50, 0x01},
+		{0x10, 0x02, 0x01, 0x02, 0x50, 0x01},
+	}
+	if len(transport.written) != len(expected) {
+		t.Fatalf("got %d frames, expected %d", len(transport.written), len(expected))
+	}
+	for i, frame := range transport.written {
+		if string(frame) != string(expected[i]) {
+			t.Errorf("frame %d: got % X, expected % X", i, frame, expected[i])
+		}
+	}
+}
+
+func TestEnabledStateAndStatusBit(t *testing.T) {
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		switch frame[0] {
+		case 0x11: // Channel enable state: enabled
+			return []byte{0x12, 0x02, 0x01, 0x01, byte(protocol.Host), byte(protocol.GenericUnit)}
+		case 0x90: // Status update: servo loop not active
+			return statusFrame(0x0491, 0, 0)
+		}
+		return nil
+	})
+
+	enabled, err := controller.IsEnabled(1)
+	if err != nil {
+		t.Fatalf("IsEnabled: %v", err)
+	}
+	status, err := controller.GetDCStatusUpdate(1)
+	if err != nil {
+		t.Fatalf("GetDCStatusUpdate: %v", err)
+	}
+	servo := controller.ParseDCStatusBits(status.StatusBits).IsEnabled
+	if !enabled || servo {
+		t.Errorf("got channel enabled %t and servo bit %t, expected true and false", enabled, servo)
+	}
+}
+
+func TestChannelBitmask(t *testing.T) {
+	controller, transport := newFakeController(func(frame []byte) []byte {
+		if frame[0] != 0x11 {
+			return nil
+		}
+		return echoHeader(frame)
+	})
+
+	for _, channel := range []uint8{0, 2, 255} {
+		if err := controller.Enable(channel, true); !errors.Is(err, protocol.ErrChannelNotSupported) {
+			t.Errorf("Enable channel %d: got %v, expected ErrChannelNotSupported", channel, err)
+		}
+		if _, err := controller.IsEnabled(channel); !errors.Is(err, protocol.ErrChannelNotSupported) {
+			t.Errorf("IsEnabled channel %d: got %v, expected ErrChannelNotSupported", channel, err)
+		}
+	}
+	if len(transport.written) != 0 {
+		t.Fatalf("got %d frames written for invalid channels, expected none", len(transport.written))
+	}
+
+	if err := controller.Enable(1, true); err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	if _, err := controller.IsEnabled(1); err != nil {
+		t.Fatalf("IsEnabled: %v", err)
+	}
+	for _, frame := range transport.written {
+		if frame[2] != 0x01 {
+			t.Errorf("frame % X: got channel ident 0x%02X, expected 0x01", frame, frame[2])
+		}
+	}
+	controller.MaxChannels = 2
+	if err := controller.Enable(2, true); err != nil {
+		t.Fatalf("Enable channel 2 of 2: %v", err)
+	}
+	if ident := transport.written[len(transport.written)-1][2]; ident != 0x02 {
+		t.Errorf("got channel ident 0x%02X, expected 0x02", ident)
+	}
+	if err := controller.Enable(3, true); err == nil || err.Error() != "channel not supported: 3" {
+		t.Errorf("got %v, expected channel 3 reported as not supported", err)
+	}
+}
+
+func TestApplyDefaultProfile(t *testing.T) {
+	controller, transport := newFakeController(nil)
+	if err := controller.ApplyDefaultProfile(1); err != nil {
+		t.Fatalf("ApplyDefaultProfile: %v", err)
+	}
+	expected := controller.VelocityToCounts(protocol.DefaultVelocityProfile(protocol.MTS25Z8).MaxVelocity)
+	frame := transport.written[0]
+	if frame[0] != 0x13 || binary.LittleEndian.Uint32(frame[16:20]) != expected {
+		t.Errorf("got % X, expected a velocity parameters frame with maximum velocity %d", frame, expected)
+	}
+	controller.StageType = "unknown"
+	if err := controller.ApplyDefaultProfile(1); !errors.Is(err, protocol.ErrUnknownStageType) {
+		t.Errorf("got %v, expected ErrUnknownStageType", err)
+	}
+}
+
+func TestSetTrapezoidalVelocityVerified(t *testing.T) {
+	store := storeParameters(0x0413)
+	clamp := false
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		if clamp && frame[0] == 0x13 {
+			frame = append([]byte{}, frame...)
+			binary.LittleEndian.PutUint32(frame[16:20], 1000) // Maximum velocity clamped by the firmware
+		}
+		return store(frame)
+	})
+	profile := protocol.VelocityProfile{MinVelocity: 0, MaxVelocity: 2, Acceleration: 1.5}
+
+	if err := controller.SetTrapezoidalVelocityVerified(1, profile, 1e-9); err != nil {
+		t.Errorf("SetTrapezoidalVelocityVerified: %v", err)
+	}
+	clamp = true
+	if err := controller.SetTrapezoidalVelocityVerified(1, profile, 0.01); !errors.Is(err, protocol.ErrReadbackMismatch) {
+		t.Errorf("got %v, expected ErrReadbackMismatch", err)
+	}
+}
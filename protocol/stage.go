@@ -0,0 +1,78 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+import "fmt"
+
+type MotorType string
+type StageType string
+
+const (
+	Brushed   MotorType = "Brushed"
+	Brushless MotorType = "Brushless"
+
+	MTS25Z8 StageType = "MTS25-Z8"
+	MTS50Z8 StageType = "MTS50-Z8"
+	Z8xx    StageType = "Z8xx"
+	Z6xx    StageType = "Z6xx"
+	PRM1Z8  StageType = "PRM1-Z8"
+	PRMTZ8  StageType = "PRMTZ8"
+	CR1Z7   StageType = "CR1-Z7"
+	KVS30   StageType = "KVS30"
+)
+
+var ErrUnknownStageType = fmt.Errorf("unknown stage type")
+var ErrUnknownMotorType = fmt.Errorf("unknown motor type")
+
+/*
+Changes the stage type used for unit conversions without
+reconnecting, e.g. after swapping the actuator. The stage
+must be listed in StageScalingFactor. The conversions read
+the stage type without locking, so like assigning the field
+this must not run while other goroutines issue commands
+*/
+func (k *KDC101) SetStageType(stage StageType) error {
+	if _, ok := StageScalingFactor[string(stage)]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownStageType, stage)
+	}
+	k.StageType = string(stage)
+	return nil
+}
+
+/*
+Changes the motor type used for unit conversions without
+reconnecting. The motor must be listed in MotorTFactor.
+Like SetStageType, it must not run concurrently with commands
+*/
+func (k *KDC101) SetMotorType(motor MotorType) error {
+	if _, ok := MotorTFactor[string(motor)]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownMotorType, motor)
+	}
+	k.MotorType = string(motor)
+	return nil
+}
+
+/*
+Returns the default velocity profile of the stage listed in
+StageDefaultVelocity, or a zero profile for unknown stages
+*/
+func DefaultVelocityProfile(stage StageType) VelocityProfile {
+	return StageDefaultVelocity[string(stage)]
+}
+
+/*
+Sets the default velocity profile of the configured stage on
+the specified channel, returning ErrUnknownStageType if the
+stage has none
+*/
+func (k *KDC101) ApplyDefaultProfile(channel uint8) error {
+	profile, ok := StageDefaultVelocity[k.StageType]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownStageType, k.StageType)
+	}
+	return k.SetTrapezoidalVelocity(channel, profile)
+}
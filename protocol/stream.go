@@ -0,0 +1,234 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+)
+
+/*
+Interval between acknowledgements sent while streaming. The
+controller stops sending status messages once it has sent
+about 50 of them without an acknowledgement, and the APT
+protocol asks for one at least once per second
+*/
+const StatusAckInterval = 500 * time.Millisecond
+
+/*
+Starts the unsolicited status update messages. Once started,
+the controller sends a DC status update every 100 ms
+*/
+func (k *KDC101) StartUpdateMessages() error {
+	return k.WriteHeaderOnly(HeaderMessage{
+		ID:          msgHwStartUpdateMsgs,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+}
+
+/*
+Stops the unsolicited status update messages
+*/
+func (k *KDC101) StopUpdateMessages() error {
+	return k.WriteHeaderOnly(HeaderMessage{
+		ID:          msgHwStopUpdateMsgs,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+}
+
+/*
+Stops the unsolicited end of move messages (move completed,
+move stopped and homed), so the only frames received are
+answers to requests, which keeps status polling predictable
+*/
+func (k *KDC101) SuspendEndOfMoveMessages() error {
+	return k.WriteHeaderOnly(HeaderMessage{
+		ID:          msgMotSuspendEndOfMoveMsgs,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+}
+
+/*
+Resumes the unsolicited end of move messages, which is the
+default state of the controller when powered up
+*/
+func (k *KDC101) ResumeEndOfMoveMessages() error {
+	return k.WriteHeaderOnly(HeaderMessage{
+		ID:          msgMotResumeEndOfMoveMsgs,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+}
+
+/*
+Acknowledges the DC status updates sent by the controller.
+While update messages are enabled this must be sent at
+least once per second, otherwise the controller stops
+sending them after about 50 (see StatusAckInterval)
+*/
+func (k *KDC101) AckDCStatusUpdate() error {
+	return k.WriteHeaderOnly(HeaderMessage{
+		ID:          msgMotAckDCStatusUpdate,
+		Destination: GenericUnit,
+		Source:      Host,
+	})
+}
+
+/*
+Lightweight keepalive meant to be called on a timer. The
+KDC101 has no watchdog stopping motion when the host goes
+quiet; its only host supervision is the server alive
+acknowledgement, without which it stops sending status
+messages (e.g. move completed) over USB once it has sent
+about 50 of them (see StatusAckInterval). Keepalive sends
+that acknowledgement, then pings the controller, so a dead
+link, or a stale frame answering in its place, is reported
+as an error. Neither affects the motor
+*/
+func (k *KDC101) Keepalive() error {
+	if err := k.AckDCStatusUpdate(); err != nil {
+		return err
+	}
+	return k.Ping()
+}
+
+/*
+Starts the update messages and streams every DC status
+update received on the returned channel. Acknowledgements
+are sent every StatusAckInterval, right after a frame has
+been read or a read has timed out: serial writes discard
+the bytes received so far, so sending one while an update
+is arriving would cut it. Read timeouts are skipped, since
+an update may just arrive late, and bytes are discarded up
+to the next valid header when a frame was cut or garbled.
+Streaming stops, and the channel is closed, when the
+context is cancelled, Close is called or a read fails
+otherwise
+*/
+func (k *KDC101) StreamStatus(ctx context.Context) (<-chan DCStatusUpdate, error) {
+	if err := k.StartUpdateMessages(); err != nil {
+		return nil, err
+	}
+	updates := make(chan DCStatusUpdate)
+	k.goBackground(func(done <-chan struct{}) {
+		defer close(updates)
+		defer k.StopUpdateMessages()
+
+		acked := time.Now()
+		for ctx.Err() == nil && !closed(done) {
+			response, err := k.readStreamFrame()
+			if time.Since(acked) >= StatusAckInterval {
+				if err := k.AckDCStatusUpdate(); err != nil {
+					k.logf("acknowledging status updates: %v", err)
+				}
+				acked = time.Now()
+			}
+			if errors.Is(err, ErrHeaderOnlyFrame) || errors.Is(err, ErrHardwareResponse) {
+				continue
+			}
+			if errors.Is(err, ErrTimeout) || errors.Is(err, os.ErrDeadlineExceeded) {
+				continue // No whole frame within the read timeout
+			}
+			if err != nil {
+				return
+			}
+			if response.ID != msgMotGetDCStatusUpdate {
+				continue
+			}
+			update, err := k.parseDCStatusUpdate(response.Data)
+			if err != nil {
+				continue
+			}
+			select {
+			case updates <- update:
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			}
+		}
+	})
+	return updates, nil
+}
+
+/*
+Reads the next frame of a stream of unsolicited messages.
+Bytes are discarded one at a time until the 6 last read
+form a valid header, so the tail of a frame cut by a read
+timeout or by a write resetting the input is skipped
+instead of being decoded as the next frame
+*/
+func (k *KDC101) readStreamFrame() (DataMessage, error) {
+	k.ioMutex.Lock()
+	defer k.ioMutex.Unlock()
+
+	deadline := time.Now().Add(k.frameTimeout())
+	header, err := k.readFull(6, deadline)
+	discarded := 0
+	for err == nil && !isFrameHeader(header) {
+		var next []byte
+		next, err = k.readFull(1, deadline)
+		header = append(header[1:], next...)
+		discarded++
+	}
+	if discarded > 0 {
+		k.logf("rx discarded %d bytes to find the next header", discarded)
+	}
+	if err != nil {
+		k.countRead(err)
+		return InvalidData, err
+	}
+	return k.readDataAfter(header, deadline)
+}
+
+/*
+Tells whether 6 bytes are the header of a frame sent by the
+controller: a known message addressed to the host
+*/
+func isFrameHeader(header []byte) bool {
+	_, known := messageNames[uint16(header[1])<<8|uint16(header[0])]
+	return known && Endpoint(header[4]&0x7F) == Host
+}
+
+/*
+Reads the move completed message (0x0464) sent at the end
+of a relative or absolute move while end of move messages
+are enabled, and decodes the final status it carries
+*/
+func (k *KDC101) ReadMoveCompleted() (DCStatusUpdate, error) {
+	return k.readMoveEvent(msgMotMoveCompleted)
+}
+
+/*
+Reads the move stopped message (0x0466) sent when a move is
+stopped, by a command or a limit switch, while end of move
+messages are enabled, and decodes the final status it
+carries
+*/
+func (k *KDC101) ReadMoveStopped() (DCStatusUpdate, error) {
+	return k.readMoveEvent(msgMotMoveStopped)
+}
+
+/*
+Reads a data message and decodes its status, returning
+ErrUnexpectedMessageID if it is not the given event
+*/
+func (k *KDC101) readMoveEvent(id uint16) (DCStatusUpdate, error) {
+	response, err := k.ReadData()
+	if err != nil {
+		return DCStatusUpdate{}, err
+	}
+	if err := expectResponse(response, id); err != nil {
+		return DCStatusUpdate{}, err
+	}
+	return k.parseDCStatusUpdate(response.Data)
+}
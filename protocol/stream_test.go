@@ -0,0 +1,176 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/thorlabs-kdc101/protocol"
+)
+
+/*
+Serial port receiving a status update every period, the
+bytes of each one arriving byteDelay apart, with the frame
+index as position. Like unicomm serial writes, a write
+discards every byte received and not read yet
+*/
+type serialStream struct {
+	start     time.Time
+	period    time.Duration
+	byteDelay time.Duration
+	consumed  int
+	acks      int
+
+	mutex sync.Mutex
+}
+
+func (s *serialStream) Connect() error    { return nil }
+func (s *serialStream) Disconnect() error { return nil }
+func (s *serialStream) IsConnected() bool { return true }
+
+func (s *serialStream) ReadUntil(delimiter string) ([]byte, error) {
+	return nil, nil
+}
+
+/*
+Returns the number of bytes received since the start
+*/
+func (s *serialStream) received() int {
+	elapsed := time.Since(s.start)
+	frames := int(elapsed / s.period)
+	arrived := int((elapsed-time.Duration(frames)*s.period)/s.byteDelay) + 1
+	return frames*20 + min(arrived, 20)
+}
+
+func (s *serialStream) Read(size uint) ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var data []byte
+	for ; len(data) < int(size) && s.consumed < s.received(); s.consumed++ {
+		frame := statusFrame(0x0491, int32(s.consumed/20), 0)
+		data = append(data, frame[s.consumed%20])
+	}
+	return data, nil
+}
+
+func (s *serialStream) Write(message []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if message[0] == 0x92 && message[1] == 0x04 {
+		s.acks++
+	}
+	s.consumed = s.received()
+	return nil
+}
+
+func TestKeepalive(t *testing.T) {
+	controller, transport := newFakeController(func(frame []byte) []byte {
+		if frame[0] != 0x11 || frame[1] != 0x02 {
+			return nil
+		}
+		return echoHeader(frame)
+	})
+	if err := controller.Keepalive(); err != nil {
+		t.Fatalf("Keepalive: %v", err)
+	}
+	if ack, query := transport.written[0], transport.written[1]; ack[0] != 0x92 || query[0] != 0x11 {
+		t.Errorf("got % X and % X, expected the server alive acknowledgement then the enable state query", ack, query)
+	}
+	transport.respond = nil
+	if err := controller.Keepalive(); !errors.Is(err, protocol.ErrTimeout) {
+		t.Errorf("got %v, expected ErrTimeout without an answer", err)
+	}
+	transport.pending = []byte{0x44, 0x04, 0x01, 0x00, 0x01, 0x50} // Stale homed message
+	if err := controller.Keepalive(); !errors.Is(err, protocol.ErrUnexpectedMessageID) {
+		t.Errorf("got %v, expected ErrUnexpectedMessageID for a stale frame", err)
+	}
+}
+
+func TestStreamStatusAcksBetweenFrames(t *testing.T) {
+	transport := &serialStream{start: time.Now(), period: 20 * time.Millisecond, byteDelay: 500 * time.Microsecond}
+	controller := &protocol.KDC101{Communication: transport, StageType: "MTS25-Z8"}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*protocol.StatusAckInterval)
+	defer cancel()
+	updates, err := controller.StreamStatus(ctx)
+	if err != nil {
+		t.Fatalf("StreamStatus: %v", err)
+	}
+
+	received, last := 0, int32(-1)
+	for update := range updates {
+		if update.Position <= last {
+			t.Fatalf("got position %d after %d, expected the frames in order", update.Position, last)
+		}
+		received, last = received+1, update.Position
+	}
+	transport.mutex.Lock()
+	acks := transport.acks
+	transport.mutex.Unlock()
+	if acks < 2 {
+		t.Errorf("got %d acknowledgements, expected one every %v", acks, protocol.StatusAckInterval)
+	}
+	if expected := int(3*protocol.StatusAckInterval/transport.period) * 3 / 4; received < expected {
+		t.Errorf("got %d updates, expected at least %d", received, expected)
+	}
+}
+
+func TestStreamStatusSkipsCutFrames(t *testing.T) {
+	controller, transport := newFakeController(nil)
+	transport.pending = append(statusFrame(0x0491, 5000, 0)[9:], statusFrame(0x0491, 10000, 0)...)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates, err := controller.StreamStatus(ctx)
+	if err != nil {
+		t.Fatalf("StreamStatus: %v", err)
+	}
+
+	select {
+	case update := <-updates:
+		if update.Position != 10000 {
+			t.Errorf("got position %d, expected the update following the cut one", update.Position)
+		}
+	case <-time.After(2 * protocol.DefaultFrameTimeout):
+		t.Error("no update received after a cut frame")
+	}
+}
+
+func TestStreamStatusSurvivesReadTimeouts(t *testing.T) {
+	controller, transport := newFakeController(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates, err := controller.StreamStatus(ctx)
+	if err != nil {
+		t.Fatalf("StreamStatus: %v", err)
+	}
+
+	time.Sleep(3*protocol.DefaultFrameTimeout + protocol.StatusAckInterval)
+	transport.mutex.Lock()
+	acks := 0
+	for _, frame := range transport.written {
+		if frame[0] == 0x92 && frame[1] == 0x04 {
+			acks++
+		}
+	}
+	transport.pending = append(transport.pending, statusFrame(0x0491, 10000, 0)...)
+	transport.mutex.Unlock()
+	if acks == 0 {
+		t.Error("no acknowledgement sent while no update arrived")
+	}
+
+	select {
+	case update, ok := <-updates:
+		if !ok || update.Position != 10000 {
+			t.Errorf("got %+v (open %v), expected the late update", update, ok)
+		}
+	case <-time.After(2 * protocol.DefaultFrameTimeout):
+		t.Error("stream ended after a read timeout")
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*
+Interval between the status reads done while there are
+status subscribers
+*/
+const StatusPollInterval = 100 * time.Millisecond
+
+var ErrInvalidPollInterval = fmt.Errorf("poll intervals must be positive, fast not above slow")
+
+/*
+Subscribes to the status of channel 1, read by a single
+polling goroutine shared by every subscriber, so any number
+of consumers cost one status request per interval. The
+poller starts with the first subscriber and stops once the
+last one has unsubscribed. Updates are dropped for a
+subscriber that has not consumed the previous one, so a slow
+consumer never stalls the others. The returned function
+unsubscribes and closes the channel; it is safe to call it
+more than once
+*/
+func (k *KDC101) Subscribe() (<-chan DCStatusUpdateSI, func()) {
+	updates := make(chan DCStatusUpdateSI, 1)
+
+	k.subscribeMutex.Lock()
+	if k.subscribers == nil {
+		k.subscribers = make(map[chan DCStatusUpdateSI]struct{})
+	}
+	k.subscribers[updates] = struct{}{}
+	if !k.polling {
+		k.polling = true
+		k.goBackground(k.pollStatus)
+	}
+	k.subscribeMutex.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			k.subscribeMutex.Lock()
+			defer k.subscribeMutex.Unlock()
+			if _, ok := k.subscribers[updates]; ok {
+				delete(k.subscribers, updates)
+				close(updates) // Unless already closed by Close
+			}
+		})
+	}
+	return updates, unsubscribe
+}
+
+/*
+Polls the status while there are subscribers and fans every
+update out to them. On Close every subscriber channel is
+closed
+*/
+func (k *KDC101) pollStatus(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			k.subscribeMutex.Lock()
+			for updates := range k.subscribers {
+				close(updates)
+			}
+			clear(k.subscribers)
+			k.polling = false
+			k.subscribeMutex.Unlock()
+			return
+		case <-time.After(StatusPollInterval):
+		}
+
+		k.subscribeMutex.Lock()
+		if len(k.subscribers) == 0 {
+			k.polling = false
+			k.subscribeMutex.Unlock()
+			return
+		}
+		k.subscribeMutex.Unlock()
+
+		status, err := k.GetStatusUpdateSI(1)
+		if err != nil {
+			continue
+		}
+
+		k.subscribeMutex.Lock()
+		for updates := range k.subscribers {
+			select {
+			case updates <- status:
+			default: // Previous update not consumed yet
+			}
+		}
+		k.subscribeMutex.Unlock()
+	}
+}
+
+/*
+Polls the status of channel 1 at an interval adapted to the
+motion: every fast interval while the last status showed the
+motor moving or homing, every slow one while idle, so idle
+traffic drops without delaying the detection of a move end.
+The first status is read before returning, so a controller
+that does not answer is reported right away. Read errors
+later on are skipped, and the channel is closed when the
+context is done or the controller is closed
+*/
+func (k *KDC101) AdaptivePoll(ctx context.Context, fast, slow time.Duration) (<-chan DCStatusUpdateSI, error) {
+	if fast <= 0 || slow < fast {
+		return nil, ErrInvalidPollInterval
+	}
+	status, err := k.GetStatusUpdateSI(1)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(chan DCStatusUpdateSI, 1)
+	k.goBackground(func(done <-chan struct{}) {
+		defer close(updates)
+		for {
+			select {
+			case updates <- status:
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			}
+
+			interval := slow
+			if inMotion(status.StatusBits) {
+				interval = fast
+			}
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-done:
+					return
+				case <-time.After(interval):
+				}
+				if status, err = k.GetStatusUpdateSI(1); err == nil {
+					break
+				}
+			}
+		}
+	})
+	return updates, nil
+}
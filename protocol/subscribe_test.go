@@ -0,0 +1,114 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/thorlabs-kdc101/protocol"
+)
+
+func TestSubscribersShareOnePoller(t *testing.T) {
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		if frame[0] != 0x90 {
+			return nil
+		}
+		return statusFrame(0x0491, 0, 0)
+	})
+
+	first, unsubscribeFirst := controller.Subscribe()
+	second, unsubscribeSecond := controller.Subscribe()
+	for _, updates := range []<-chan protocol.DCStatusUpdateSI{first, second} {
+		select {
+		case <-updates:
+		case <-time.After(time.Second):
+			t.Fatal("no status update received")
+		}
+	}
+	unsubscribeFirst()
+	unsubscribeSecond()
+	unsubscribeSecond()
+	for range first {
+		// Drain an update still buffered when unsubscribing
+	}
+
+	time.Sleep(3 * protocol.StatusPollInterval)
+	sent := controller.Metrics().CommandsSent
+	time.Sleep(3 * protocol.StatusPollInterval)
+	if controller.Metrics().CommandsSent != sent {
+		t.Error("status still polled after the last subscriber left")
+	}
+}
+
+func TestCloseStopsBackgroundGoroutines(t *testing.T) {
+	controller, _ := newFakeController(nil)
+	updates, unsubscribe := controller.Subscribe()
+	defer unsubscribe()
+	if err := controller.OnPositionReached(1, 10, 0.1, func() {}); err != nil {
+		t.Fatalf("OnPositionReached: %v", err)
+	}
+
+	if err := controller.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	select {
+	case _, ok := <-updates:
+		if ok {
+			t.Error("got a status update, expected the channel to be closed")
+		}
+	default:
+		t.Error("subscription channel still open after Close")
+	}
+	sent := controller.Metrics().CommandsSent
+	time.Sleep(3 * protocol.StatusPollInterval)
+	if controller.Metrics().CommandsSent != sent {
+		t.Error("status still polled after Close")
+	}
+}
+
+func TestAdaptivePoll(t *testing.T) {
+	var reads atomic.Int32
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		if frame[0] != 0x90 {
+			return nil
+		}
+		var bits uint32
+		if reads.Add(1) <= 3 {
+			bits = 0x10 // Moving clockwise for the first three reads
+		}
+		return statusFrame(0x0491, 0, bits)
+	})
+	if _, err := controller.AdaptivePoll(context.Background(), time.Second, time.Millisecond); !errors.Is(err, protocol.ErrInvalidPollInterval) {
+		t.Errorf("got %v, expected ErrInvalidPollInterval", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates, err := controller.AdaptivePoll(ctx, 5*time.Millisecond, time.Hour)
+	if err != nil {
+		t.Fatalf("AdaptivePoll: %v", err)
+	}
+	for i := range 4 {
+		select {
+		case <-updates:
+		case <-time.After(time.Second):
+			t.Fatalf("update %d not received at the fast interval", i)
+		}
+	}
+	select {
+	case <-updates:
+		t.Error("idle status polled at the fast interval")
+	case <-time.After(50 * time.Millisecond):
+	}
+	cancel()
+	for range updates {
+		// Closed once the context is cancelled
+	}
+}
@@ -1,14 +1,38 @@
 /*
 Author: Leonardo Rossi Leao
 Created at: September 26th, 2025
-Last update: September 26th, 2025
+Last update: October 16th, 2026
 */
 
 package protocol
 
+import (
+	"fmt"
+
+	"github.com/devicehub-go/thorlabs-kdc101/internal/utils"
+)
+
+var ErrOutOfTravelRange = fmt.Errorf("position out of travel range")
+
+/*
+Sampling interval T of the KDC101 servo loop in seconds,
+used to scale velocities and accelerations. Per the APT
+protocol it is a property of the controller, not of the
+stage or motor: brushed DC controllers such as the KDC101
+sample every 2048 / 6 MHz. The stage only contributes the
+encoder counts per unit (see StageScalingFactor)
+*/
+const SamplingInterval = 2048.0 / (6.0 * 1e6)
+
+/*
+Deprecated: the sampling interval depends on the controller,
+see SamplingInterval. Every entry holds the KDC101 value, so
+MotorType no longer affects unit conversions; the table only
+lists the motor types SetMotorType accepts
+*/
 var MotorTFactor = map[string]float64{
-	"Brushed":   2048.0 / (6.0 * 1e6),
-	"Brushless": 2048.0 / (6.0 * 1e6),
+	"Brushed":   SamplingInterval,
+	"Brushless": SamplingInterval,
 }
 
 var StageScalingFactor = map[string]float64{
@@ -22,6 +46,47 @@ var StageScalingFactor = map[string]float64{
 	"KVS30":    20000.0,
 }
 
+/*
+Travel range of each stage in millimeters (degrees for the
+rotation mounts). Series entries use the longest model
+*/
+var StageTravelRange = map[string][2]float64{
+	"MTS25-Z8": {0, 25},
+	"MTS50-Z8": {0, 50},
+	"Z8xx":     {0, 25},
+	"Z6xx":     {0, 25},
+	"PRM1-Z8":  {0, 360},
+	"PRMTZ8":   {0, 360},
+	"CR1-Z7":   {0, 360},
+	"KVS30":    {0, 30},
+}
+
+/*
+Safe starting velocity profile of each stage, in mm/s and
+mm/s² (degrees for the rotation mounts). The values stay
+below the maximum velocity rated for the stage, so a first
+move never runs at a dangerous speed; raise them once the
+application is known to tolerate it
+*/
+var StageDefaultVelocity = map[string]VelocityProfile{
+	"MTS25-Z8": {MaxVelocity: 2.0, Acceleration: 1.5},
+	"MTS50-Z8": {MaxVelocity: 2.0, Acceleration: 1.5},
+	"Z8xx":     {MaxVelocity: 2.0, Acceleration: 1.5},
+	"Z6xx":     {MaxVelocity: 1.0, Acceleration: 1.0},
+	"PRM1-Z8":  {MaxVelocity: 10.0, Acceleration: 10.0},
+	"PRMTZ8":   {MaxVelocity: 10.0, Acceleration: 10.0},
+	"CR1-Z7":   {MaxVelocity: 10.0, Acceleration: 10.0},
+	"KVS30":    {MaxVelocity: 4.0, Acceleration: 4.0},
+}
+
+/*
+Motor current scaling in milliamps per count. The APT
+protocol reports the motor current of the DC status update
+directly in mA (range -32768 to +32767); legacy controllers
+designed before 2020 leave it unused
+*/
+const MotorCurrentScale = 1.0
+
 /*
 Converts position in millimeters to encoder counts
 */
@@ -44,8 +109,7 @@ counts per second
 */
 func (k *KDC101) VelocityToCounts(velocity float64) uint32 {
 	encCount := StageScalingFactor[k.StageType]
-	T := MotorTFactor[k.MotorType]
-	return uint32(velocity * T * 65536 * encCount)
+	return uint32(velocity * SamplingInterval * 65536 * encCount)
 }
 
 /*
@@ -53,8 +117,7 @@ Converts encoder counts per second to velocity in millimeters
 */
 func (k *KDC101) CountsToVelocity(counts uint32) float64 {
 	encCount := StageScalingFactor[k.StageType]
-	T := MotorTFactor[k.MotorType]
-	return float64(counts) / (T * 65536 * encCount)
+	return float64(counts) / (SamplingInterval * 65536 * encCount)
 }
 
 /*
@@ -63,8 +126,7 @@ encoder counts per second squared
 */
 func (k *KDC101) AccelerationToCounts(acceleration float64) uint32 {
 	encCount := StageScalingFactor[k.StageType]
-	T := MotorTFactor[k.MotorType]
-	return uint32(acceleration * (T * T) * 65536 * encCount)
+	return uint32(acceleration * (SamplingInterval * SamplingInterval) * 65536 * encCount)
 }
 
 /*
@@ -73,6 +135,119 @@ in millimeters per second squared
 */
 func (k *KDC101) CountsToAcceleration(counts int32) float64 {
 	encCount := StageScalingFactor[k.StageType]
-	T := MotorTFactor[k.MotorType]
-	return float64(counts) / (T * T * 65536 * encCount)
-}
\ No newline at end of file
+	return float64(counts) / (SamplingInterval * SamplingInterval * 65536 * encCount)
+}
+
+/*
+Returns the velocity profile the device will actually use
+once the values are quantized to encoder counts, so it can
+be compared with the profile read back from the device
+*/
+func (k *KDC101) SnapToAchievable(profile VelocityProfile) VelocityProfile {
+	return VelocityProfile{
+		MinVelocity:  k.CountsToVelocity(k.VelocityToCounts(profile.MinVelocity)),
+		MaxVelocity:  k.CountsToVelocity(k.VelocityToCounts(profile.MaxVelocity)),
+		Acceleration: k.CountsToAcceleration(int32(k.AccelerationToCounts(profile.Acceleration))),
+	}
+}
+
+/*
+Converts the motor current reported by the controller to
+milliamps
+*/
+func (k *KDC101) CountsToCurrent(counts int16) float64 {
+	return float64(counts) * MotorCurrentScale
+}
+
+/*
+Returns the encoder counts per unit of the configured stage
+and the sampling interval used to scale velocities, which
+is SamplingInterval whatever the motor type. A zero count
+means the stage type is unknown
+*/
+func (k *KDC101) ScalingFactors() (encCountsPerUnit float64, motorTFactor float64) {
+	return StageScalingFactor[k.StageType], SamplingInterval
+}
+
+/*
+Returns the encoder counts per unit (mm or degree) of the
+configured stage, or zero if the stage type is unknown
+*/
+func (k *KDC101) CountsPerUnit() float64 {
+	return StageScalingFactor[k.StageType]
+}
+
+/*
+Returns the smallest commandable move of the configured
+stage, one encoder count, in its units. Zero if the stage
+type is unknown
+*/
+func (k *KDC101) MinStep() float64 {
+	counts := k.CountsPerUnit()
+	if counts == 0 {
+		return 0
+	}
+	return 1 / counts
+}
+
+/*
+Returns the travel range of the configured stage. Both
+limits are zero if the stage type is unknown
+*/
+func (k *KDC101) TravelRange() (float64, float64) {
+	travel := StageTravelRange[k.StageType]
+	return travel[0], travel[1]
+}
+
+/*
+Returns the end of the travel range of the configured stage
+in encoder counts, or zero if the stage type is unknown
+*/
+func (k *KDC101) MaxTravelCounts() int32 {
+	_, high := k.TravelRange()
+	return k.PositionToCounts(high)
+}
+
+/*
+Clamps an absolute target to [0, MaxTravelCounts] when
+ClampMoves is set and the stage is a known linear one
+*/
+func (k *KDC101) clampCounts(counts int32) int32 {
+	limit := k.MaxTravelCounts()
+	if !k.ClampMoves || limit == 0 || k.IsRotary() {
+		return counts
+	}
+	clamped := min(max(counts, 0), limit)
+	if clamped != counts {
+		k.logf("target %d clamped to %d", counts, clamped)
+	}
+	return clamped
+}
+
+/*
+Returns an error if travel validation is enabled and the
+position is outside the travel range of the stage. Moves
+clamped by ClampMoves are not validated, nor are those of
+rotation mounts, which turn continuously
+*/
+func (k *KDC101) validateTravel(position float64) error {
+	low, high := k.TravelRange()
+	if !k.ValidateTravel || k.ClampMoves || low == high || k.IsRotary() {
+		return nil
+	}
+	if position < low || position > high {
+		return fmt.Errorf("%w: %g outside [%g, %g]", ErrOutOfTravelRange, position, low, high)
+	}
+	return nil
+}
+
+/*
+Returns the codec for the data fields, in the configured
+byte order
+*/
+func (k *KDC101) codec() utils.Codec {
+	if k.ByteOrder == nil {
+		return utils.LittleEndian
+	}
+	return utils.Codec{Order: k.ByteOrder}
+}
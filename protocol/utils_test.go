@@ -0,0 +1,99 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol_test
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/devicehub-go/thorlabs-kdc101/protocol"
+)
+
+func TestDCStatusUpdateToSICurrent(t *testing.T) {
+	controller := &protocol.KDC101{StageType: "MTS25-Z8", MotorType: "Brushed"}
+
+	for _, counts := range []int16{0, 250, -120, 32767, -32768} {
+		statusSI := controller.DCStatusUpdateToSI(protocol.DCStatusUpdate{Current: counts})
+		expected := float64(counts) * protocol.MotorCurrentScale
+		if statusSI.Current != expected {
+			t.Errorf("current %d: got %v mA, expected %v mA", counts, statusSI.Current, expected)
+		}
+	}
+}
+
+func TestDCServoScaling(t *testing.T) {
+	// Scaling factors for 1 mm/s and 1 mm/s² listed in the APT protocol
+	cases := []struct {
+		stage        string
+		motor        string
+		velocity     uint32
+		acceleration uint32
+	}{
+		{"MTS25-Z8", "Brushed", 772981, 263},
+		{"MTS50-Z8", "Brushed", 772981, 263},
+		{"Z6xx", "Brushed", 550292, 187},
+		{"PRM1-Z8", "Brushed", 42941, 14},
+		{"KVS30", "Brushed", 447392, 152},
+		{"KVS30", "Brushless", 447392, 152}, // The motor type does not change the KDC101 scaling
+	}
+	for _, c := range cases {
+		controller := &protocol.KDC101{StageType: c.stage, MotorType: c.motor}
+		if counts := controller.VelocityToCounts(1); counts != c.velocity {
+			t.Errorf("%s/%s velocity: got %d, expected %d", c.stage, c.motor, counts, c.velocity)
+		}
+		if counts := controller.AccelerationToCounts(1); counts != c.acceleration {
+			t.Errorf("%s/%s acceleration: got %d, expected %d", c.stage, c.motor, counts, c.acceleration)
+		}
+	}
+}
+
+func TestBigEndianFields(t *testing.T) {
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		// Position counter of 0x00010000 counts sent big-endian
+		return []byte{0x12, 0x04, 0x06, 0x00, 0x81, 0x50, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00}
+	})
+	controller.ByteOrder = binary.BigEndian
+
+	position, err := controller.GetPosition(1)
+	if err != nil {
+		t.Fatalf("GetPosition: %v", err)
+	}
+	if expected := controller.CountsToPosition(0x00010000); position != expected {
+		t.Errorf("got position %v, expected %v", position, expected)
+	}
+}
+
+func TestRotaryWrap(t *testing.T) {
+	controller, transport := newFakeController(func(frame []byte) []byte {
+		if frame[0] != 0x11 {
+			return nil
+		}
+		counts := protocol.StageScalingFactor["PRM1-Z8"] * 710 // 350° on the second turn
+		response := []byte{0x12, 0x04, 0x06, 0x00, 0x81, 0x50, 0x01, 0x00}
+		return binary.LittleEndian.AppendUint32(response, uint32(int32(counts)))
+	})
+	controller.StageType = "PRM1-Z8"
+	controller.AngleWrap = protocol.Wrap360
+
+	position, err := controller.GetPosition(1)
+	if err != nil {
+		t.Fatalf("GetPosition: %v", err)
+	}
+	if math.Abs(position-350) > controller.MinStep() {
+		t.Errorf("got position %v, expected 350", position)
+	}
+
+	if err := controller.MoveToAngleShortestPath(1, 10); err != nil {
+		t.Fatalf("MoveToAngleShortestPath: %v", err)
+	}
+	move := transport.written[len(transport.written)-1]
+	counts := int32(binary.LittleEndian.Uint32(move[8:12]))
+	if distance := controller.CountsToPosition(counts); math.Abs(distance-20) > controller.MinStep() {
+		t.Errorf("got a relative move of %v°, expected +20°", distance)
+	}
+}
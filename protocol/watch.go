@@ -0,0 +1,115 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+/*
+Interval between the status reads done while position
+callbacks are registered
+*/
+const PositionPollInterval = 100 * time.Millisecond
+
+type positionWatcher struct {
+	channel   uint8
+	target    float64
+	tolerance float64
+	callback  func()
+}
+
+/*
+Registers a callback invoked once, from a polling goroutine,
+when the stage has stopped within tolerance of the target
+position: it is neither moving, jogging nor homing. Each
+watched channel is polled for its own status. Any number of
+callbacks can be registered; each one is removed after it
+fires
+*/
+func (k *KDC101) OnPositionReached(channel uint8, target float64, tolerance float64, cb func()) error {
+	if _, err := k.channelBitmask(channel); err != nil {
+		return err
+	}
+	k.watchMutex.Lock()
+	defer k.watchMutex.Unlock()
+
+	k.watchers = append(k.watchers, positionWatcher{
+		channel:   channel,
+		target:    target,
+		tolerance: tolerance,
+		callback:  cb,
+	})
+	if !k.watching {
+		k.watching = true
+		k.goBackground(k.watchPositions)
+	}
+	return nil
+}
+
+/*
+Polls the status while there are position callbacks
+registered and fires the ones whose condition is met. On
+Close the pending callbacks are dropped
+*/
+func (k *KDC101) watchPositions(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			k.watchMutex.Lock()
+			k.watchers = nil
+			k.watching = false
+			k.watchMutex.Unlock()
+			return
+		case <-time.After(PositionPollInterval):
+		}
+
+		k.watchMutex.Lock()
+		if len(k.watchers) == 0 {
+			k.watching = false
+			k.watchMutex.Unlock()
+			return
+		}
+		var channels []uint8
+		for _, watcher := range k.watchers {
+			if !slices.Contains(channels, watcher.channel) {
+				channels = append(channels, watcher.channel)
+			}
+		}
+		k.watchMutex.Unlock()
+
+		// Position of each watched channel that is at rest
+		stopped := map[uint8]float64{}
+		for _, channel := range channels {
+			status, err := k.GetDCStatusUpdate(channel)
+			if err != nil || inMotion(k.ParseDCStatusBits(status.StatusBits)) {
+				continue
+			}
+			stopped[channel] = k.CountsToPosition(status.Position)
+		}
+
+		var reached []func()
+		k.watchMutex.Lock()
+		pending := k.watchers[:0]
+		for _, watcher := range k.watchers {
+			position, ok := stopped[watcher.channel]
+			if ok && math.Abs(position-watcher.target) <= watcher.tolerance {
+				reached = append(reached, watcher.callback)
+			} else {
+				pending = append(pending, watcher)
+			}
+		}
+		k.watchers = pending
+		k.watchMutex.Unlock()
+
+		for _, callback := range reached {
+			callback()
+		}
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Author: Leonardo Rossi Leao
+Created at: October 16th, 2026
+Last update: October 16th, 2026
+*/
+
+package protocol_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/devicehub-go/thorlabs-kdc101/protocol"
+)
+
+func TestPositionCallbackWaitsForJogEnd(t *testing.T) {
+	var jogging atomic.Bool
+	jogging.Store(true)
+	controller, _ := newFakeController(func(frame []byte) []byte {
+		if frame[0] != 0x90 {
+			return nil
+		}
+		var bits uint32
+		if jogging.Load() {
+			bits = 0x40 // Jogging forward, in motion bits clear
+		}
+		return statusFrame(0x0491, 0, bits)
+	})
+	fired := make(chan struct{})
+	if err := controller.OnPositionReached(1, 0, 0.1, func() { close(fired) }); err != nil {
+		t.Fatalf("OnPositionReached: %v", err)
+	}
+	defer controller.Close()
+
+	select {
+	case <-fired:
+		t.Fatal("callback fired while jogging")
+	case <-time.After(3 * protocol.PositionPollInterval):
+	}
+	jogging.Store(false)
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Error("callback not fired once the jog ended")
+	}
+}
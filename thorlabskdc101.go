@@ -1,32 +1,34 @@
 /*
 Author: Leonardo Rossi Leao
 Created at: September 26th, 2025
-Last update: September 26th, 2025
+Last update: October 16th, 2026
 */
 
 package thorlabskdc101
 
 import (
+	"io"
+
 	"github.com/devicehub-go/thorlabs-kdc101/protocol"
 	"github.com/devicehub-go/unicomm"
 )
 
 type KDC101 = protocol.KDC101
-type MotorType string
-type StageType string
+type MotorType = protocol.MotorType
+type StageType = protocol.StageType
 
 const (
-	Brushed   MotorType = "Brushed"
-	Brushless MotorType = "Brushless"
-
-	MTS25Z8 StageType = "MTS25-Z8"
-	MTS50Z8 StageType = "MTS50-Z8"
-	Z8xx    StageType = "Z8xx"
-	Z6xx    StageType = "Z6xx"
-	PRM1Z8  StageType = "PRM1-Z8"
-	PRMTZ8  StageType = "PRMTZ8"
-	CR1Z7   StageType = "CR1-Z7"
-	KVS30   StageType = "KVS30"
+	Brushed   = protocol.Brushed
+	Brushless = protocol.Brushless
+
+	MTS25Z8 = protocol.MTS25Z8
+	MTS50Z8 = protocol.MTS50Z8
+	Z8xx    = protocol.Z8xx
+	Z6xx    = protocol.Z6xx
+	PRM1Z8  = protocol.PRM1Z8
+	PRMTZ8  = protocol.PRMTZ8
+	CR1Z7   = protocol.CR1Z7
+	KVS30   = protocol.KVS30
 )
 
 /*
@@ -41,3 +43,34 @@ func New(stage StageType, motor MotorType, options unicomm.UnicommOptions) *KDC1
 	}
 	return oem750
 }
+
+/*
+Creates a new instance of KDC101 in dry run mode: frames are
+recorded instead of written to hardware and can be retrieved
+with SentFrames, while requests are answered as by an idle,
+homed stage following the moves sent (see DryRunTransport)
+*/
+func NewDryRun(stage StageType, motor MotorType) *KDC101 {
+	return &KDC101{
+		Communication: &protocol.DryRunTransport{},
+		StageType:     string(stage),
+		MotorType:     string(motor),
+	}
+}
+
+/*
+Creates a new instance of KDC101 replaying a recording made
+with StartRecording: every frame sent must match the
+recorded one, and requests receive the recorded responses
+*/
+func NewReplay(stage StageType, motor MotorType, recording io.Reader) (*KDC101, error) {
+	transport, err := protocol.NewReplayTransport(recording)
+	if err != nil {
+		return nil, err
+	}
+	return &KDC101{
+		Communication: transport,
+		StageType:     string(stage),
+		MotorType:     string(motor),
+	}, nil
+}
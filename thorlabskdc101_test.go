@@ -1,13 +1,15 @@
 /*
 Author: Leonardo Rossi Leao
 Created at: September 26th, 2025
-Last update: September 26th, 2025
+Last update: October 16th, 2026
 */
 
 package thorlabskdc101_test
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"log"
 	"testing"
 	"time"
@@ -66,4 +68,24 @@ func TestMoveMotor(t *testing.T) {
     if err := controller.MoveAbsolutePosition(1, 10.0); err != nil {
         log.Fatal("Failed to move:", err)
     }
-}
\ No newline at end of file
+}
+
+func TestDryRunBlockingMoves(t *testing.T) {
+	controller := kdc101.NewDryRun(kdc101.MTS25Z8, kdc101.Brushed)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := controller.HomeAndWait(ctx, 1, time.Second); err != nil {
+		t.Fatalf("HomeAndWait: %v", err)
+	}
+	if err := controller.MoveAbsoluteAndWait(ctx, 1, 10, 0); err != nil {
+		t.Fatalf("MoveAbsoluteAndWait: %v", err)
+	}
+	position, err := controller.GetPosition(1)
+	if err != nil || math.Abs(position-10) > controller.MinStep() {
+		t.Errorf("got %v, %v, expected the position moved to", position, err)
+	}
+	if err := controller.MoveAbsoluteWaitCompleted(1, 5, 0); err != nil {
+		t.Errorf("MoveAbsoluteWaitCompleted: %v", err)
+	}
+}